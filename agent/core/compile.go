@@ -19,6 +19,8 @@ package core
 
 import (
 	//go:nolint
+	_ "bytes"
+	_ "context"
 	_ "encoding/base64"
 	_ "fmt"
 	_ "log"
@@ -26,7 +28,9 @@ import (
 	_ "math/rand"
 	_ "net"
 	_ "os"
+	_ "os/signal"
 	_ "reflect"
+	_ "regexp"
 	_ "runtime"
 	_ "runtime/debug"
 	_ "runtime/metrics"
@@ -35,7 +39,9 @@ import (
 	_ "strings"
 	_ "sync"
 	_ "sync/atomic"
+	_ "syscall"
 	_ "time"
+	_ "unicode/utf8"
 	_ "unsafe"
 
 	//go:nolint
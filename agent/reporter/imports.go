@@ -19,14 +19,24 @@ package reporter
 
 import (
 	// imports required packages for gRPC reporter
+	_ "bufio"
+	_ "bytes"
 	_ "context"
 	_ "crypto/tls"
 	_ "crypto/x509"
+	_ "encoding/binary"
+	_ "encoding/hex"
 	_ "fmt"
+	_ "hash/fnv"
 	_ "io"
+	_ "net/http"
 	_ "os"
+	_ "path/filepath"
+	_ "sort"
 	_ "strconv"
 	_ "strings"
+	_ "sync"
+	_ "sync/atomic"
 	_ "time"
 
 	// imports the logs for reporter
@@ -55,6 +65,20 @@ import (
 	_ "google.golang.org/grpc/stats"
 	_ "google.golang.org/grpc/status"
 
+	// imports the OpenTelemetry protocols for OTLP reporter
+	_ "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	_ "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	_ "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	_ "go.opentelemetry.io/proto/otlp/common/v1"
+	_ "go.opentelemetry.io/proto/otlp/logs/v1"
+	_ "go.opentelemetry.io/proto/otlp/metrics/v1"
+	_ "go.opentelemetry.io/proto/otlp/resource/v1"
+	_ "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	// imports the JSON format of protocols for file reporter
+	_ "google.golang.org/protobuf/encoding/protojson"
+	_ "google.golang.org/protobuf/proto"
+
 	// imports protocols between agent and backend
 	_ "skywalking.apache.org/repo/goapi/collect/agent/configuration/v3"
 	_ "skywalking.apache.org/repo/goapi/collect/common/v3"
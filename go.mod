@@ -5,16 +5,18 @@ go 1.18
 require (
 	github.com/google/uuid v1.3.0
 	github.com/pkg/errors v0.9.1
+	go.opentelemetry.io/proto/otlp v0.19.0
 	google.golang.org/grpc v1.55.0
+	google.golang.org/protobuf v1.30.0
 	skywalking.apache.org/repo/goapi v0.0.0-20230314034821-0c5a44bb767a
 )
 
 require (
 	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/google/go-cmp v0.5.9 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.7.0 // indirect
 	golang.org/x/net v0.10.0 // indirect
 	golang.org/x/sys v0.8.0 // indirect
 	golang.org/x/text v0.9.0 // indirect
 	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
-	google.golang.org/protobuf v1.30.0 // indirect
 )
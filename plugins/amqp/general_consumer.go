@@ -22,6 +22,7 @@ import (
 
 	"github.com/rabbitmq/amqp091-go"
 
+	"github.com/apache/skywalking-go/plugins/core/log"
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/tracing"
 )
@@ -46,9 +47,14 @@ func GeneralConsumerAfterInvoke(invocation operator.Invocation, queue, consumerT
 	channel := invocation.CallerInstance().(*nativeChannel)
 	peer := getPeerInfo(channel.connection)
 
-	span, err := tracing.CreateEntrySpan(operationName, func(headerKey string) (string, error) {
-		return deliveries.Headers[headerKey].(string), nil
-	}, tracing.WithLayer(tracing.SpanLayerMQ),
+	extractor := func(headerKey string) (string, error) {
+		value, _ := deliveries.Headers[headerKey].(string)
+		return value, nil
+	}
+	// the active entry span is reused when consuming the messages in a batch
+	active := tracing.ActiveSpan()
+	reused := active != nil && active.IsEntry()
+	span, err := tracing.CreateEntrySpan(operationName, extractor, tracing.WithLayer(tracing.SpanLayerMQ),
 		tracing.WithComponent(ConsumerComponentID),
 		tracing.WithTag(tracing.TagMQBroker, peer),
 		tracing.WithTag(tracing.TagMQQueue, queue),
@@ -61,10 +67,19 @@ func GeneralConsumerAfterInvoke(invocation operator.Invocation, queue, consumerT
 	if err != nil {
 		return err
 	}
+	// the extractor is ignored when reusing, so link the consumed message to the batch span
+	if reused {
+		if err = span.AddRef(extractor); err != nil {
+			log.Warnf("cannot link the message to the consumer span: %v", err)
+		}
+	}
 	span.SetPeer(peer)
 	if err, ok := results[1].(error); ok && err != nil {
 		span.Error(err.Error())
 	}
-	span.End()
+	// the reused span is ended by the creator after the batch consumed
+	if !reused {
+		span.End()
+	}
 	return nil
 }
@@ -0,0 +1,70 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package amqp
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-go/plugins/core"
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneralConsumerInvoke(t *testing.T) {
+	defer core.ResetTracingContext()
+	err := GeneralConsumerAfterInvoke(newTestInvocation(), "test", "consumer", nil, newTestDeliveries(core.NewTestHeader("trace-1")), nil)
+	assert.Nil(t, err, "after invoke error should be nil")
+
+	spans := core.WaitReportedSpans(1)
+	assert.Equal(t, 1, len(spans), "spans length should be 1")
+	assert.Equal(t, "AMQP/test/consumer/Consumer", spans[0].OperationName(), "operation name should be the queue and consumer tag")
+	assert.Equal(t, 1, len(spans[0].Refs()), "the message should be linked")
+	assert.Equal(t, "trace-1", spans[0].Refs()[0].GetTraceID(), "trace id of the ref not correct")
+}
+
+func TestGeneralConsumerInvokeInBatch(t *testing.T) {
+	defer core.ResetTracingContext()
+	batch, err := tracing.CreateEntrySpan("batch", func(headerKey string) (string, error) {
+		return "", nil
+	})
+	assert.Nil(t, err, "create batch span error should be nil")
+	// the message with the invalid context should be skipped
+	for _, header := range []string{core.NewTestHeader("trace-1"), "invalid", core.NewTestHeader("trace-2")} {
+		err = GeneralConsumerAfterInvoke(newTestInvocation(), "test", "consumer", nil, newTestDeliveries(header), nil)
+		assert.Nil(t, err, "after invoke error should be nil")
+	}
+	batch.End()
+
+	spans := core.WaitReportedSpans(1)
+	assert.Equal(t, 1, len(spans), "spans length should be 1")
+	assert.Equal(t, 2, len(spans[0].Refs()), "every valid message should be linked to the batch span")
+	assert.Equal(t, "trace-1", spans[0].Refs()[0].GetTraceID(), "trace id of the first ref not correct")
+	assert.Equal(t, "trace-2", spans[0].Refs()[1].GetTraceID(), "trace id of the second ref not correct")
+}
+
+func newTestInvocation() operator.Invocation {
+	return operator.NewInvocation(&nativeChannel{connection: &nativeConnection{}})
+}
+
+func newTestDeliveries(header string) <-chan Delivery {
+	deliveries := make(chan Delivery, 1)
+	deliveries <- Delivery{Headers: Table{core.Header: header}, ConsumerTag: "consumer"}
+	return deliveries
+}
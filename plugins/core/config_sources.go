@@ -0,0 +1,142 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"sync"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+// the sources of the dynamic configuration, the source with the larger value has the higher precedence
+const (
+	configSourceBackend = iota
+	configSourceFile
+	configSourceCount
+)
+
+// configSources merges the dynamic configs from the backend and the local file, the local file overrides the backend.
+// Every source only changes its own configs, and the watchers are notified serially when the merged value changed.
+type configSources struct {
+	lock     sync.Mutex
+	watchers map[string]reporter.AgentConfigChangeWatcher
+	sources  [configSourceCount]map[string]string
+	applied  map[string]string
+}
+
+func newConfigSources(watchers []reporter.AgentConfigChangeWatcher) *configSources {
+	s := &configSources{
+		watchers: make(map[string]reporter.AgentConfigChangeWatcher),
+		applied:  make(map[string]string),
+	}
+	for i := range s.sources {
+		s.sources[i] = make(map[string]string)
+	}
+	for _, watcher := range watchers {
+		s.watchers[watcher.Key()] = watcher
+	}
+	return s
+}
+
+// sourceWatchers creates the watchers for binding to the config discovery service of the source
+func (s *configSources) sourceWatchers(source int) []reporter.AgentConfigChangeWatcher {
+	result := make([]reporter.AgentConfigChangeWatcher, 0, len(s.watchers))
+	for key := range s.watchers {
+		result = append(result, &sourceConfigWatcher{sources: s, source: source, key: key})
+	}
+	return result
+}
+
+// handleConfigs replaces all configs of the source, the keys not in the configs are removed from the source
+func (s *configSources) handleConfigs(source int, configs map[string]string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sources[source] = make(map[string]string, len(configs))
+	for key, value := range configs {
+		if value != "" {
+			s.sources[source][key] = value
+		}
+	}
+	for key := range s.watchers {
+		s.apply(key)
+	}
+}
+
+// changeConfig changes one config of the source, the config is removed from the source when the value is empty
+func (s *configSources) changeConfig(source int, key, value string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if value == "" {
+		delete(s.sources[source], key)
+	} else {
+		s.sources[source][key] = value
+	}
+	s.apply(key)
+}
+
+func (s *configSources) sourceValue(source int, key string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sources[source][key]
+}
+
+// apply notifies the watcher when the merged value changed, the caller should hold the lock
+func (s *configSources) apply(key string) {
+	watcher := s.watchers[key]
+	if watcher == nil {
+		return
+	}
+	value := ""
+	for source := configSourceCount - 1; source >= 0; source-- {
+		if v := s.sources[source][key]; v != "" {
+			value = v
+			break
+		}
+	}
+	if value == s.applied[key] {
+		return
+	}
+	s.applied[key] = value
+	if value == "" {
+		watcher.Notify(reporter.DELETED, "")
+	} else {
+		watcher.Notify(reporter.MODIFY, value)
+	}
+}
+
+// sourceConfigWatcher changes the config of the source, the value is the config of the source instead of the merged one
+type sourceConfigWatcher struct {
+	sources *configSources
+	source  int
+	key     string
+}
+
+func (w *sourceConfigWatcher) Key() string {
+	return w.key
+}
+
+func (w *sourceConfigWatcher) Notify(eventType reporter.AgentConfigEventType, newValue string) {
+	if eventType == reporter.DELETED {
+		newValue = ""
+	}
+	w.sources.changeConfig(w.source, w.key, newValue)
+}
+
+func (w *sourceConfigWatcher) Value() string {
+	return w.sources.sourceValue(w.source, w.key)
+}
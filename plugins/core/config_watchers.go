@@ -0,0 +1,133 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+// configWatcher applies the changed value of the config key, and restores the default value when the config is deleted
+type configWatcher struct {
+	lock         sync.Mutex
+	key          string
+	defaultValue string
+	current      string
+	apply        func(value string) error
+}
+
+func newConfigWatcher(key, defaultValue string, apply func(value string) error) *configWatcher {
+	return &configWatcher{key: key, defaultValue: defaultValue, current: defaultValue, apply: apply}
+}
+
+func (w *configWatcher) Key() string {
+	return w.key
+}
+
+func (w *configWatcher) Notify(eventType reporter.AgentConfigEventType, newValue string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if eventType == reporter.DELETED {
+		newValue = w.defaultValue
+	}
+	// keep the current value when the new value is invalid
+	if err := w.apply(newValue); err != nil {
+		return
+	}
+	w.current = newValue
+}
+
+func (w *configWatcher) Value() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.current
+}
+
+// loadStrings reads the []string value, returns nil when the value is not stored
+func loadStrings(value *atomic.Value) []string {
+	result, _ := value.Load().([]string)
+	return result
+}
+
+// correlationConfig reads the current correlation limits, nothing could be set when the limits are not stored
+func (t *Tracer) correlationConfig() *CorrelationConfig {
+	if correlation, ok := t.correlation.Load().(*CorrelationConfig); ok && correlation != nil {
+		return correlation
+	}
+	return &CorrelationConfig{}
+}
+
+// initConfigWatchers registers the watchers of the agent configs which could be changed at runtime,
+// and the watchers registered by the plugins
+func (t *Tracer) initConfigWatchers(ignoreSuffixStr, ignorePath string) {
+	// the correlation limits are changed by two watchers, copy and replace the limits in the lock
+	var correlationLock sync.Mutex
+	correlation := t.correlationConfig()
+	t.cdsWatchers = append(t.cdsWatchers,
+		newConfigWatcher("agent.ignore_suffix", ignoreSuffixStr, func(value string) error {
+			t.ignoreSuffix.Store(strings.Split(value, ","))
+			return nil
+		}),
+		newConfigWatcher("agent.trace_ignore_path", ignorePath, func(value string) error {
+			t.traceIgnorePath.Store(strings.Split(value, ","))
+			return nil
+		}),
+		newConfigWatcher("agent.correlation.max_key_count", strconv.Itoa(correlation.MaxKeyCount), func(value string) error {
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			correlationLock.Lock()
+			defer correlationLock.Unlock()
+			t.correlation.Store(&CorrelationConfig{MaxKeyCount: count, MaxValueSize: t.correlationConfig().MaxValueSize})
+			return nil
+		}),
+		newConfigWatcher("agent.correlation.max_value_size", strconv.Itoa(correlation.MaxValueSize), func(value string) error {
+			size, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			correlationLock.Lock()
+			defer correlationLock.Unlock()
+			t.correlation.Store(&CorrelationConfig{MaxKeyCount: t.correlationConfig().MaxKeyCount, MaxValueSize: size})
+			return nil
+		}),
+		newConfigWatcher("log.reporter.enable", strconv.FormatBool(t.logReportEnabled()), func(value string) error {
+			enable, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			t.changeLogReportEnabled(enable)
+			return nil
+		}),
+		newConfigWatcher("log.reporter.level", t.logReportMinLevel(), func(value string) error {
+			level := strings.ToLower(strings.TrimSpace(value))
+			if _, ok := logLevelOrders[level]; !ok && level != "" {
+				return fmt.Errorf("unknown log level: %s", value)
+			}
+			t.logReportLevel.Store(level)
+			return nil
+		}),
+	)
+	t.cdsWatchers = append(t.cdsWatchers, t.tools.configWatchers...)
+}
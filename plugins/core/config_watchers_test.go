@@ -0,0 +1,93 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+
+	"github.com/stretchr/testify/assert"
+
+	common "skywalking.apache.org/repo/goapi/collect/common/v3"
+)
+
+type logTestContext struct{}
+
+func (c *logTestContext) GetServiceName() string    { return "service" }
+func (c *logTestContext) GetInstanceName() string   { return "instance" }
+func (c *logTestContext) GetTraceID() string        { return "trace" }
+func (c *logTestContext) GetTraceSegmentID() string { return "segment" }
+func (c *logTestContext) GetSpanID() int32          { return 0 }
+func (c *logTestContext) GetEndPointName() string   { return "endpoint" }
+
+func newConfigCommand(uuid string, configs map[string]string) *common.Command {
+	command := &common.Command{Args: []*common.KeyStringValuePair{{Key: "UUID", Value: uuid}}}
+	for k, v := range configs {
+		command.Args = append(command.Args, &common.KeyStringValuePair{Key: k, Value: v})
+	}
+	return command
+}
+
+func TestConfigWatchers(t *testing.T) {
+	rep := NewStoreReporter()
+	tracer := &Tracer{Reporter: rep, ServiceEntity: &reporter.Entity{}, tools: NewTracerTools(), meterMap: &sync.Map{}}
+	tracer.correlation.Store(&CorrelationConfig{MaxKeyCount: 3, MaxValueSize: 128})
+	tracer.InitLogReporter(true, "")
+	var pluginValue string
+	tracer.tools.WatchConfig("plugin.config.test.value", "default", func(value string) error {
+		pluginValue = value
+		return nil
+	})
+	tracer.initConfigWatchers(".jpg", "/health")
+	cds := reporter.NewConfigDiscoveryService()
+	cds.BindWatchers(tracer.cdsWatchers)
+
+	cds.HandleCommand(newConfigCommand("1", map[string]string{
+		"agent.ignore_suffix":              ".png,.css",
+		"agent.trace_ignore_path":          "/ping/**",
+		"agent.correlation.max_key_count":  "5",
+		"agent.correlation.max_value_size": "invalid",
+		"log.reporter.level":               "error",
+		"plugin.config.test.value":         "changed",
+	}))
+	assert.Equal(t, []string{".png", ".css"}, loadStrings(&tracer.ignoreSuffix))
+	assert.Equal(t, []string{"/ping/**"}, loadStrings(&tracer.traceIgnorePath))
+	assert.Equal(t, 5, tracer.correlationConfig().MaxKeyCount)
+	assert.Equal(t, 128, tracer.correlationConfig().MaxValueSize, "the invalid value should be ignored")
+	assert.Equal(t, "changed", pluginValue)
+	tracer.ReportLog(&logTestContext{}, time.Now(), "info", "ignored", nil)
+	tracer.ReportLog(&logTestContext{}, time.Now(), "error", "reported", nil)
+	assert.Equal(t, 1, len(rep.Logs))
+
+	cds.HandleCommand(newConfigCommand("2", map[string]string{
+		"log.reporter.enable": "false",
+	}))
+	assert.Equal(t, []string{".jpg"}, loadStrings(&tracer.ignoreSuffix), "the deleted config should be restored")
+	assert.Equal(t, []string{"/health"}, loadStrings(&tracer.traceIgnorePath))
+	assert.Equal(t, 3, tracer.correlationConfig().MaxKeyCount)
+	assert.Equal(t, "default", pluginValue)
+	tracer.ReportLog(&logTestContext{}, time.Now(), "error", "disabled", nil)
+	assert.Equal(t, 1, len(rep.Logs))
+
+	cds.HandleCommand(newConfigCommand("3", map[string]string{}))
+	tracer.ReportLog(&logTestContext{}, time.Now(), "debug", "restored", nil)
+	assert.Equal(t, 2, len(rep.Logs))
+}
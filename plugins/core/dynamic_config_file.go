@@ -0,0 +1,196 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InitDynamicConfigFile uses the local file as a source of the dynamic configuration, such as a file mounted from the ConfigMap.
+// The file is checked in every interval(seconds), and the watchers are notified when the content changed.
+// The configs in the file override the same configs from the backend.
+// It's a properties file when the file name ends with ".properties", otherwise it's a YAML file.
+func (t *Tracer) InitDynamicConfigFile(path string, checkIntervalSecond int) {
+	if path == "" || checkIntervalSecond <= 0 || !t.InitSuccess() {
+		return
+	}
+	source := &dynamicConfigFile{path: path, sources: t.configSources, log: t.Log}
+	source.check()
+	go func() {
+		for {
+			time.Sleep(time.Duration(checkIntervalSecond) * time.Second)
+			if !t.InitSuccess() {
+				// the tracer has been shutdown
+				return
+			}
+			source.check()
+		}
+	}()
+}
+
+type dynamicConfigFile struct {
+	path    string
+	sources *configSources
+	log     *LogWrapper
+	content string
+	lastErr string
+}
+
+// check reads the file and notifies the watchers when the content changed,
+// the current configs are kept when the file cannot be read or parsed
+func (f *dynamicConfigFile) check() {
+	content, err := os.ReadFile(f.path)
+	if err != nil {
+		f.warnOnce(fmt.Sprintf("cannot read the dynamic config file %s: %v", f.path, err))
+		return
+	}
+	if string(content) == f.content {
+		return
+	}
+	f.content = string(content)
+	configs, err := parseDynamicConfigs(f.path, f.content)
+	if err != nil {
+		f.warnOnce(fmt.Sprintf("cannot parse the dynamic config file %s: %v", f.path, err))
+		return
+	}
+	f.lastErr = ""
+	f.sources.handleConfigs(configSourceFile, configs)
+}
+
+// warnOnce logs the error only when it's different from the last one, avoid logging the same error in every check
+func (f *dynamicConfigFile) warnOnce(msg string) {
+	if msg == f.lastErr {
+		return
+	}
+	f.lastErr = msg
+	f.log.Warn(msg)
+}
+
+func parseDynamicConfigs(path, content string) (map[string]string, error) {
+	if strings.HasSuffix(path, ".properties") {
+		return parsePropertiesConfigs(content), nil
+	}
+	return parseYAMLConfigs(content)
+}
+
+// parsePropertiesConfigs parses the "key=value" or "key: value" per line, the line starts with "#" or "!" is comment
+func parsePropertiesConfigs(content string) map[string]string {
+	configs := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		inx := strings.IndexAny(line, "=:")
+		if inx <= 0 {
+			continue
+		}
+		configs[strings.TrimSpace(line[:inx])] = strings.TrimSpace(line[inx+1:])
+	}
+	return configs
+}
+
+type yamlConfigLevel struct {
+	indent int
+	key    string
+}
+
+// parseYAMLConfigs parses the mappings of the YAML, the nested keys are joined by ".",
+// such as "agent: {sample_rate: 0.5}" is parsed as "agent.sample_rate=0.5". The lists are not supported.
+func parseYAMLConfigs(content string) (map[string]string, error) {
+	configs := make(map[string]string)
+	parents := make([]yamlConfigLevel, 0)
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			return nil, fmt.Errorf("line %d: the list is not supported", i+1)
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.HasPrefix(line[indent:], "\t") {
+			return nil, fmt.Errorf("line %d: the tab is not allowed for indentation", i+1)
+		}
+		key, value, ok := splitYAMLKeyValue(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("line %d: the mapping key is not found", i+1)
+		}
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		if value == "" {
+			parents = append(parents, yamlConfigLevel{indent: indent, key: key})
+			continue
+		}
+		fullKey := key
+		if len(parents) > 0 {
+			keys := make([]string, 0, len(parents)+1)
+			for _, p := range parents {
+				keys = append(keys, p.key)
+			}
+			fullKey = strings.Join(append(keys, key), ".")
+		}
+		parsed, err := parseYAMLScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		configs[fullKey] = parsed
+	}
+	return configs, nil
+}
+
+func splitYAMLKeyValue(line string) (key, value string, ok bool) {
+	if strings.HasSuffix(line, ":") {
+		return strings.TrimSpace(line[:len(line)-1]), "", true
+	}
+	inx := strings.Index(line, ": ")
+	if inx <= 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(line[:inx]), strings.TrimSpace(line[inx+2:]), true
+}
+
+// parseYAMLScalar removes the quotes or the comment of the value
+func parseYAMLScalar(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "\""):
+		end := strings.LastIndex(value, "\"")
+		if end == 0 {
+			return "", fmt.Errorf("the quote is not closed: %s", value)
+		}
+		return strconv.Unquote(value[:end+1])
+	case strings.HasPrefix(value, "'"):
+		end := strings.LastIndex(value, "'")
+		if end == 0 {
+			return "", fmt.Errorf("the quote is not closed: %s", value)
+		}
+		return strings.ReplaceAll(value[1:end], "''", "'"), nil
+	}
+	if inx := strings.Index(value, " #"); inx >= 0 {
+		value = strings.TrimSpace(value[:inx])
+	}
+	if value == "~" || value == "null" {
+		return "", nil
+	}
+	return value, nil
+}
@@ -0,0 +1,125 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDynamicConfigs(t *testing.T) {
+	configs, err := parseDynamicConfigs("config.yaml", `
+# comment
+agent:
+  sample_rate: 0.5 # inline comment
+  correlation:
+    max_key_count: "5"
+  trace_ignore_path: '/health/**'
+log.reporter.level: error
+plugin:
+  config:
+    http.server_collect_parameters: true
+`)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"agent.sample_rate":                            "0.5",
+		"agent.correlation.max_key_count":              "5",
+		"agent.trace_ignore_path":                      "/health/**",
+		"log.reporter.level":                           "error",
+		"plugin.config.http.server_collect_parameters": "true",
+	}, configs)
+
+	_, err = parseDynamicConfigs("config.yaml", "agent:\n  - sample_rate: 0.5")
+	assert.NotNil(t, err, "the list should not be supported")
+
+	configs, err = parseDynamicConfigs("config.properties", `
+# comment
+agent.sample_rate=0.5
+log.reporter.level: error
+`)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"agent.sample_rate": "0.5", "log.reporter.level": "error"}, configs)
+}
+
+func TestDynamicConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	var value string
+	sources := newConfigSources([]reporter.AgentConfigChangeWatcher{newConfigWatcher("agent.test", "default", func(v string) error {
+		value = v
+		return nil
+	})})
+	source := &dynamicConfigFile{path: path, sources: sources, log: &LogWrapper{newDefaultLogger()}}
+
+	// the file is not existing
+	source.check()
+	assert.Equal(t, "", value)
+
+	assert.Nil(t, os.WriteFile(path, []byte("agent:\n  test: changed\n"), 0o600))
+	source.check()
+	assert.Equal(t, "changed", value)
+
+	// keep the current configs when the file is invalid
+	assert.Nil(t, os.WriteFile(path, []byte("agent:\n  - test\n"), 0o600))
+	source.check()
+	assert.Equal(t, "changed", value)
+
+	assert.Nil(t, os.WriteFile(path, []byte("agent:\n"), 0o600))
+	source.check()
+	assert.Equal(t, "default", value)
+}
+
+func TestConfigSources(t *testing.T) {
+	values := make(map[string]string)
+	notified := 0
+	watchers := make([]reporter.AgentConfigChangeWatcher, 0)
+	for _, key := range []string{"agent.backend", "agent.file", "agent.both"} {
+		k := key
+		watchers = append(watchers, newConfigWatcher(k, "default", func(v string) error {
+			values[k] = v
+			notified++
+			return nil
+		}))
+	}
+	sources := newConfigSources(watchers)
+	cds := reporter.NewConfigDiscoveryService()
+	cds.BindWatchers(sources.sourceWatchers(configSourceBackend))
+
+	cds.HandleCommand(newConfigCommand("1", map[string]string{"agent.backend": "backend", "agent.both": "backend"}))
+	sources.handleConfigs(configSourceFile, map[string]string{"agent.file": "file", "agent.both": "file"})
+	assert.Equal(t, map[string]string{"agent.backend": "backend", "agent.file": "file", "agent.both": "file"}, values,
+		"the file should override the backend")
+
+	// the backend changes do not reset the configs of the file
+	cds.HandleCommand(newConfigCommand("2", map[string]string{"agent.backend": "backend", "agent.both": "changed"}))
+	assert.Equal(t, map[string]string{"agent.backend": "backend", "agent.file": "file", "agent.both": "file"}, values)
+	notifiedCount := notified
+	cds.HandleCommand(newConfigCommand("3", map[string]string{"agent.backend": "backend", "agent.both": "changed"}))
+	assert.Equal(t, notifiedCount, notified, "the watchers should not be notified when the merged value not changed")
+
+	// the backend config is used after the file config removed
+	sources.handleConfigs(configSourceFile, map[string]string{})
+	assert.Equal(t, map[string]string{"agent.backend": "backend", "agent.file": "default", "agent.both": "changed"}, values)
+
+	cds.HandleCommand(newConfigCommand("4", map[string]string{}))
+	assert.Equal(t, map[string]string{"agent.backend": "default", "agent.file": "default", "agent.both": "default"}, values)
+}
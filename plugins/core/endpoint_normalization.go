@@ -0,0 +1,223 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// the placeholder of the collapsed path segments
+	collapsedPathSegment = "{id}"
+	// the hex segment shorter than it is treated as a word, such as "cafe" or "added"
+	minCollapsedHexLength = 16
+)
+
+// endpointNormalizer normalizes the names of the entry spans before the span created, to reduce the cardinality of endpoints
+// when the plugin names the span by the raw path instead of the route template.
+type endpointNormalizer struct {
+	autoCollapse int32        // collapse the numeric, UUID and hex path segments of the HTTP endpoints when it's 1
+	rules        atomic.Value // []*endpointNormalizeRule
+}
+
+type endpointNormalizeRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// InitEndpointNormalization configures the normalization of the entry span names, the invalid rules are ignored,
+// and registers the watchers for updating them through the dynamic configuration.
+func (t *Tracer) InitEndpointNormalization(autoCollapse bool, rules string) {
+	t.endpointNormalizer.setAutoCollapse(autoCollapse)
+	if parsed, err := parseEndpointNormalizeRules(rules); err != nil {
+		t.Log.Warnf("ignore the invalid endpoint normalization rules: %v", err)
+	} else {
+		t.endpointNormalizer.rules.Store(parsed)
+	}
+	t.cdsWatchers = append(t.cdsWatchers,
+		newConfigWatcher("agent.endpoint_normalization.auto_collapse", strconv.FormatBool(autoCollapse), func(value string) error {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			t.endpointNormalizer.setAutoCollapse(enabled)
+			return nil
+		}),
+		newConfigWatcher("agent.endpoint_normalization.rules", rules, func(value string) error {
+			parsed, err := parseEndpointNormalizeRules(value)
+			if err != nil {
+				return err
+			}
+			t.endpointNormalizer.rules.Store(parsed)
+			return nil
+		}))
+}
+
+func (n *endpointNormalizer) setAutoCollapse(enabled bool) {
+	var val int32
+	if enabled {
+		val = 1
+	}
+	atomic.StoreInt32(&n.autoCollapse, val)
+}
+
+// normalize applies the regex rules in order, then collapses the path segments of the HTTP endpoint
+func (n *endpointNormalizer) normalize(operationName string) string {
+	rules, _ := n.rules.Load().([]*endpointNormalizeRule)
+	for _, rule := range rules {
+		operationName = rule.pattern.ReplaceAllString(operationName, rule.replacement)
+	}
+	if atomic.LoadInt32(&n.autoCollapse) == 1 {
+		operationName = collapseEndpointPath(operationName)
+	}
+	return operationName
+}
+
+// parseEndpointNormalizeRules parses the rules formatted as "regex=>replacement", multiple rules split by ";"
+func parseEndpointNormalizeRules(rules string) ([]*endpointNormalizeRule, error) {
+	result := make([]*endpointNormalizeRule, 0)
+	for _, rule := range strings.Split(rules, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		index := strings.LastIndex(rule, "=>")
+		if index <= 0 {
+			return nil, fmt.Errorf("the endpoint normalization rule %q should be formatted as \"regex=>replacement\"", rule)
+		}
+		pattern, err := regexp.Compile(strings.TrimSpace(rule[:index]))
+		if err != nil {
+			return nil, fmt.Errorf("the regex of endpoint normalization rule %q is invalid: %v", rule, err)
+		}
+		result = append(result, &endpointNormalizeRule{pattern: pattern, replacement: strings.TrimSpace(rule[index+2:])})
+	}
+	return result, nil
+}
+
+// collapseEndpointPath replaces the numeric, UUID and hex path segments with "{id}",
+// only for the HTTP endpoints named as "METHOD:/path" or "METHOD:scheme://host/path", the query string is kept.
+func collapseEndpointPath(operationName string) string {
+	pathStart := httpEndpointPathIndex(operationName)
+	if pathStart < 0 {
+		return operationName
+	}
+	pathEnd := strings.IndexAny(operationName[pathStart:], "?#")
+	if pathEnd < 0 {
+		pathEnd = len(operationName)
+	} else {
+		pathEnd += pathStart
+	}
+	segments := strings.Split(operationName[pathStart:pathEnd], "/")
+	changed := false
+	for i, segment := range segments {
+		if isIdentifierSegment(segment) {
+			segments[i] = collapsedPathSegment
+			changed = true
+		}
+	}
+	if !changed {
+		return operationName
+	}
+	return operationName[:pathStart] + strings.Join(segments, "/") + operationName[pathEnd:]
+}
+
+// httpEndpointPathIndex returns the start index of the path in the HTTP endpoint name, or -1 when it's not an HTTP endpoint
+func httpEndpointPathIndex(operationName string) int {
+	methodEnd := strings.IndexByte(operationName, ':')
+	if methodEnd <= 0 {
+		return -1
+	}
+	for i := 0; i < methodEnd; i++ {
+		if operationName[i] < 'A' || operationName[i] > 'Z' {
+			return -1
+		}
+	}
+	path := operationName[methodEnd+1:]
+	if strings.HasPrefix(path, "/") {
+		return methodEnd + 1
+	}
+	// the full URL, skip the scheme and host
+	schemeEnd := strings.Index(path, "://")
+	if schemeEnd <= 0 {
+		return -1
+	}
+	hostEnd := strings.IndexByte(path[schemeEnd+3:], '/')
+	if hostEnd < 0 {
+		return -1
+	}
+	return methodEnd + 1 + schemeEnd + 3 + hostEnd
+}
+
+func isIdentifierSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	return isNumericSegment(segment) || isUUIDSegment(segment) || isHexSegment(segment)
+}
+
+func isNumericSegment(segment string) bool {
+	for i := 0; i < len(segment); i++ {
+		if segment[i] < '0' || segment[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isUUIDSegment(segment string) bool {
+	if len(segment) != 36 {
+		return false
+	}
+	for i := 0; i < len(segment); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if segment[i] != '-' {
+				return false
+			}
+		default:
+			if !isHexChar(segment[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isHexSegment checks the long hex segment contains any digit, such as the object ID or hash
+func isHexSegment(segment string) bool {
+	if len(segment) < minCollapsedHexLength {
+		return false
+	}
+	hasDigit := false
+	for i := 0; i < len(segment); i++ {
+		if !isHexChar(segment[i]) {
+			return false
+		}
+		if segment[i] >= '0' && segment[i] <= '9' {
+			hasDigit = true
+		}
+	}
+	return hasDigit
+}
+
+func isHexChar(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,99 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+)
+
+func TestEndpointNormalize(t *testing.T) {
+	tests := []struct {
+		autoCollapse bool
+		rules        string
+		name         string
+		expected     string
+	}{
+		{true, "", "GET:/users/123/orders/456", "GET:/users/{id}/orders/{id}"},
+		{true, "", "GET:/items/6ba7b810-9dad-11d1-80b4-00c04fd430c8", "GET:/items/{id}"},
+		{true, "", "GET:/objects/507f1f77bcf86cd799439011?verbose=1", "GET:/objects/{id}?verbose=1"},
+		{true, "", "GET:http://localhost:8080/users/123", "GET:http://localhost:8080/users/{id}"},
+		{true, "", "GET:/cafe/added/v2", "GET:/cafe/added/v2"},
+		{true, "", "GET:/", "GET:/"},
+		{true, "", "Kafka/123/Consumer/group", "Kafka/123/Consumer/group"},
+		{false, "", "GET:/users/123", "GET:/users/123"},
+		{false, "^GET:/files/.*$=>GET:/files/**", "GET:/files/a/b.txt", "GET:/files/**"},
+		{true, "/v[0-9]+/=>/{version}/;/users/([^/]+)/profile=>/users/{name}/profile",
+			"GET:/v1/users/alice/profile/42", "GET:/{version}/users/{name}/profile/{id}"},
+		{false, "/(users|groups)/[0-9]+=>/$1/{id}", "POST:/groups/7", "POST:/groups/{id}"},
+	}
+	for _, test := range tests {
+		n := &endpointNormalizer{}
+		n.setAutoCollapse(test.autoCollapse)
+		rules, err := parseEndpointNormalizeRules(test.rules)
+		assert.NoError(t, err)
+		n.rules.Store(rules)
+		assert.Equal(t, test.expected, n.normalize(test.name), test.name)
+	}
+}
+
+func TestParseEndpointNormalizeRules(t *testing.T) {
+	rules, err := parseEndpointNormalizeRules("a=>b; ;c=>")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rules))
+	for _, invalid := range []string{"a", "=>b", "(=>b"} {
+		_, err = parseEndpointNormalizeRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestEntrySpanNormalized(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.InitEndpointNormalization(true, "")
+	defer Tracing.InitEndpointNormalization(false, "")
+	span, err := tracing.CreateEntrySpan("GET:/users/123", func(key string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	span.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "GET:/users/{id}", spans[0].OperationName())
+}
+
+func TestEntrySpanIgnoredBeforeNormalized(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.InitEndpointNormalization(true, "")
+	defer Tracing.InitEndpointNormalization(false, "")
+	Tracing.traceIgnorePath.Store([]string{"GET:/users/123/avatar"})
+	defer Tracing.traceIgnorePath.Store([]string(nil))
+	// the ignore path is written against the raw path, the collapsed name should not bypass it
+	ignored, err := tracing.CreateEntrySpan("GET:/users/123/avatar", func(key string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	ignored.End()
+	span, err := tracing.CreateEntrySpan("GET:/users/456/avatar", func(key string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	span.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "GET:/users/{id}/avatar", spans[0].OperationName())
+}
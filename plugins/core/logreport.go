@@ -18,6 +18,8 @@
 package core
 
 import (
+	"strings"
+	"sync/atomic"
 	"time"
 
 	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
@@ -37,7 +39,53 @@ type logTracingContext interface {
 	GetEndPointName() string
 }
 
+// logLevelOrders are the orders of the levels from the logrus and zap, the higher the more severe
+var logLevelOrders = map[string]int{
+	"trace":   1,
+	"debug":   2,
+	"info":    3,
+	"warn":    4,
+	"warning": 4,
+	"error":   5,
+	"dpanic":  6,
+	"panic":   6,
+	"fatal":   7,
+}
+
+// InitLogReporter changes whether to report the logs to the backend,
+// and the minimum level of the reported logs, all levels are reported when it's empty
+func (t *Tracer) InitLogReporter(enable bool, level string) {
+	t.changeLogReportEnabled(enable)
+	t.logReportLevel.Store(strings.ToLower(strings.TrimSpace(level)))
+}
+
+func (t *Tracer) changeLogReportEnabled(enable bool) {
+	var disabled int32
+	if !enable {
+		disabled = 1
+	}
+	atomic.StoreInt32(&t.logReportDisabled, disabled)
+}
+
+func (t *Tracer) logReportEnabled() bool {
+	return atomic.LoadInt32(&t.logReportDisabled) == 0
+}
+
+func (t *Tracer) logReportMinLevel() string {
+	level, _ := t.logReportLevel.Load().(string)
+	return level
+}
+
+// logLevelReportable checks the log level is not lower than the minimum level, the unknown levels are always reported
+func (t *Tracer) logLevelReportable(level string) bool {
+	order, ok := logLevelOrders[strings.ToLower(level)]
+	return !ok || order >= logLevelOrders[t.logReportMinLevel()]
+}
+
 func (t *Tracer) ReportLog(ctx, timeObj interface{}, level, msg string, labels map[string]string) {
+	if !t.logReportEnabled() || !t.logLevelReportable(level) {
+		return
+	}
 	tracingContext, ok := ctx.(logTracingContext)
 	if !ok || tracingContext == nil {
 		return
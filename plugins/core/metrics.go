@@ -36,6 +36,10 @@ func (t *Tracer) initMetricsCollect(meterCollectSecond int) {
 	go func() {
 		for {
 			time.Sleep(collectDuration)
+			if !t.InitSuccess() {
+				// the tracer has been shutdown
+				return
+			}
 
 			t.reachNotInitMetrics()
 
@@ -0,0 +1,33 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package operator
+
+// NewTestLogger returns a logger which discards all logs, for testing the components requiring a logger
+func NewTestLogger() LogOperator {
+	return &testLogger{}
+}
+
+type testLogger struct{}
+
+func (l *testLogger) WithField(key string, value interface{}) interface{} { return l }
+func (l *testLogger) Info(args ...interface{})                            {}
+func (l *testLogger) Infof(format string, args ...interface{})            {}
+func (l *testLogger) Warn(args ...interface{})                            {}
+func (l *testLogger) Warnf(format string, args ...interface{})            {}
+func (l *testLogger) Error(args ...interface{})                           {}
+func (l *testLogger) Errorf(format string, args ...interface{})           {}
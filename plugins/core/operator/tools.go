@@ -26,4 +26,6 @@ type ToolsOperator interface {
 	ParseStringArray(val string) ([]string, error)
 	Atoi(val string) (int, error)
 	NewSyncMap() interface{}
+	NewAtomicValue() interface{}
+	WatchConfig(key, defaultValue string, apply func(value string) error)
 }
@@ -23,6 +23,12 @@ type TracingOperator interface {
 	CreateExitSpan(operationName, peer string, injector interface{}, opts ...interface{}) (s interface{}, err error)
 	ActiveSpan() interface{} // to Span
 
+	CreateEntrySpanWithContext(ctx interface{}, operationName string, extractor interface{}, opts ...interface{}) (s interface{}, err error)
+	CreateLocalSpanWithContext(ctx interface{}, operationName string, opts ...interface{}) (s interface{}, err error)
+	CreateExitSpanWithContext(ctx interface{}, operationName, peer string, injector interface{}, opts ...interface{}) (s interface{}, err error)
+	ContextWithSpan(ctx, span interface{}) interface{}
+	SpanFromContext(ctx interface{}) interface{}
+
 	GetRuntimeContextValue(key string) interface{}
 	SetRuntimeContextValue(key string, value interface{})
 
@@ -32,4 +38,6 @@ type TracingOperator interface {
 
 	GetCorrelationContextValue(key string) string
 	SetCorrelationContextValue(key, val string)
+
+	AddSegmentProcessor(processor func(spans []interface{}) bool)
 }
@@ -18,6 +18,8 @@
 package reporter
 
 import (
+	"context"
+
 	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
 	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
 	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
@@ -115,4 +117,16 @@ type Reporter interface {
 	SendLog(log *logv3.LogData)
 	ConnectionStatus() ConnectionStatus
 	Close()
+	// Flush waits for the pending data taken by the sending goroutines, the reporter keeps working after flushed,
+	// returns the error of context when the pending data cannot be taken before the context done.
+	Flush(ctx context.Context) error
+	// Shutdown flushes the pending data and closes the reporter,
+	// returns the error of context when the pending data cannot be flushed before the context done.
+	Shutdown(ctx context.Context) error
+}
+
+// Spooler is implemented by the reporter which keeps the data on the disk while the backend is disconnected.
+type Spooler interface {
+	// SpoolEnabled returns true when the data is spooled while the backend is disconnected
+	SpoolEnabled() bool
 }
@@ -45,13 +45,13 @@ func (s *ConfigDiscoveryService) BindWatchers(watchers []AgentConfigChangeWatche
 
 func (s *ConfigDiscoveryService) HandleCommand(command *common.Command) {
 	var uuid string
-	var newConfigs = make(map[string]*common.KeyStringValuePair)
+	var newConfigs = make(map[string]string)
 	for _, pair := range command.GetArgs() {
 		if pair.Key == "SerialNumber" {
 		} else if pair.Key == "UUID" {
 			uuid = pair.Value
 		} else {
-			newConfigs[pair.Key] = pair
+			newConfigs[pair.Key] = pair.Value
 		}
 	}
 
@@ -61,17 +61,23 @@ func (s *ConfigDiscoveryService) HandleCommand(command *common.Command) {
 	}
 
 	// notify to all watchers
+	s.HandleConfigs(newConfigs)
+
+	// update uuid
+	s.UUID = uuid
+}
+
+// HandleConfigs notifies the watchers by the full set of the configs,
+// the watcher is notified with the DELETED event when its key is not in the configs
+func (s *ConfigDiscoveryService) HandleConfigs(configs map[string]string) {
 	for key, watcher := range s.watchers {
-		pair := newConfigs[key]
-		if pair == nil || pair.Value == "" {
+		value := configs[key]
+		if value == "" {
 			watcher.Notify(DELETED, "")
-		} else if pair.Value != watcher.Value() {
-			watcher.Notify(MODIFY, pair.Value)
+		} else if value != watcher.Value() {
+			watcher.Notify(MODIFY, value)
 		}
 	}
-
-	// update uuid
-	s.UUID = uuid
 }
 
 type AgentConfigChangeWatcher interface {
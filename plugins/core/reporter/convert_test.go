@@ -0,0 +1,86 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reporter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+type testSegmentContext struct{}
+
+func (c *testSegmentContext) GetTraceID() string                           { return "consumer-trace" }
+func (c *testSegmentContext) GetSegmentID() string                         { return "consumer-segment" }
+func (c *testSegmentContext) GetSpanID() int32                             { return 0 }
+func (c *testSegmentContext) GetParentSpanID() int32                       { return -1 }
+func (c *testSegmentContext) GetParentSegmentID() string                   { return "" }
+func (c *testSegmentContext) GetCorrelationContextValue(key string) string { return "" }
+func (c *testSegmentContext) SetCorrelationContextValue(key, value string) {}
+
+type testRef struct {
+	traceID   string
+	segmentID string
+}
+
+func (r *testRef) GetTraceID() string               { return r.traceID }
+func (r *testRef) GetParentSegmentID() string       { return r.segmentID }
+func (r *testRef) GetParentService() string         { return "producer" }
+func (r *testRef) GetParentServiceInstance() string { return "producer-instance" }
+func (r *testRef) GetParentEndpoint() string        { return "Kafka/test/Producer" }
+func (r *testRef) GetAddressUsedAtClient() string   { return "localhost:9092" }
+func (r *testRef) GetParentSpanID() int32           { return 1 }
+
+type testSpan struct {
+	refs []reporter.SpanContext
+}
+
+func (s *testSpan) Context() reporter.SegmentContext     { return &testSegmentContext{} }
+func (s *testSpan) Refs() []reporter.SpanContext         { return s.refs }
+func (s *testSpan) StartTime() int64                     { return 1000 }
+func (s *testSpan) EndTime() int64                       { return 1001 }
+func (s *testSpan) OperationName() string                { return "Kafka/test/Consumer" }
+func (s *testSpan) Peer() string                         { return "" }
+func (s *testSpan) SpanType() agentv3.SpanType           { return agentv3.SpanType_Entry }
+func (s *testSpan) SpanLayer() agentv3.SpanLayer         { return agentv3.SpanLayer_MQ }
+func (s *testSpan) IsError() bool                        { return false }
+func (s *testSpan) Tags() []*commonv3.KeyStringValuePair { return nil }
+func (s *testSpan) Logs() []*agentv3.Log                 { return nil }
+func (s *testSpan) ComponentID() int32                   { return 0 }
+
+func TestBuildSegmentObjectRefsOfDifferentTraces(t *testing.T) {
+	span := &testSpan{refs: []reporter.SpanContext{
+		&testRef{traceID: "trace-1", segmentID: "segment-1"},
+		&testRef{traceID: "trace-2", segmentID: "segment-2"},
+	}}
+	segment := reporter.BuildSegmentObject(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"},
+		[]reporter.ReportedSpan{span})
+
+	refs := segment.Spans[0].Refs
+	assert.Equal(t, 2, len(refs))
+	// every reference should keep the trace of the linked producer, not the trace of the consumer
+	assert.Equal(t, "trace-1", refs[0].TraceId)
+	assert.Equal(t, "segment-1", refs[0].ParentTraceSegmentId)
+	assert.Equal(t, "trace-2", refs[1].TraceId)
+	assert.Equal(t, "segment-2", refs[1].ParentTraceSegmentId)
+}
@@ -17,7 +17,11 @@
 
 package reporter
 
-import logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+import (
+	"context"
+
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
 
 type discardReporter struct{}
 
@@ -44,3 +48,11 @@ func (r *discardReporter) ConnectionStatus() ConnectionStatus {
 func (r *discardReporter) Close() {
 	// do nothing
 }
+func (r *discardReporter) Flush(ctx context.Context) error {
+	// do nothing
+	return nil
+}
+func (r *discardReporter) Shutdown(ctx context.Context) error {
+	// do nothing
+	return nil
+}
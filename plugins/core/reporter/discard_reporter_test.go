@@ -18,6 +18,7 @@
 package reporter_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/apache/skywalking-go/plugins/core/reporter"
@@ -35,4 +36,7 @@ func TestDiscardReporter(t *testing.T) {
 		t.Errorf("expect 0, actual is %d", status)
 	}
 	r.Close()
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Errorf("expect nil, actual is %v", err)
+	}
 }
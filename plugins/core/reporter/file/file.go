@@ -0,0 +1,288 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+const (
+	fileMaxSendQueueSize  = 5000
+	defaultFileMaxSize    = 100 * 1024 * 1024
+	defaultFileMaxBackups = 5
+	segmentFileName       = "segments.json"
+	meterFileName         = "meters.json"
+	logFileName           = "logs.json"
+)
+
+// NewFileReporter create a new reporter to write the tracing, metrics and log data into the local files of the directory.
+// Each data is written as a single line of JSON, the files are rotated by size.
+func NewFileReporter(logger operator.LogOperator, dir string, opts ...FileReporterOption) (reporter.Reporter, error) {
+	r := &fileReporter{
+		logger:           logger,
+		dir:              dir,
+		maxFileSize:      defaultFileMaxSize,
+		maxBackups:       defaultFileMaxBackups,
+		tracingSendCh:    make(chan *agentv3.SegmentObject, fileMaxSendQueueSize),
+		metricsSendCh:    make(chan []*agentv3.MeterData, fileMaxSendQueueSize),
+		logSendCh:        make(chan *logv3.LogData, fileMaxSendQueueSize),
+		connectionStatus: reporter.ConnectionStatusConnected,
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var err error
+	if r.segmentWriter, err = newRotateWriter(filepath.Join(dir, segmentFileName), r.maxFileSize, r.maxBackups); err != nil {
+		return nil, err
+	}
+	if r.meterWriter, err = newRotateWriter(filepath.Join(dir, meterFileName), r.maxFileSize, r.maxBackups); err != nil {
+		return nil, err
+	}
+	if r.logWriter, err = newRotateWriter(filepath.Join(dir, logFileName), r.maxFileSize, r.maxBackups); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type fileReporter struct {
+	reporter.SelfObserverHolder
+	entity        *reporter.Entity
+	logger        operator.LogOperator
+	dir           string
+	maxFileSize   int64
+	maxBackups    int
+	tracingSendCh chan *agentv3.SegmentObject
+	metricsSendCh chan []*agentv3.MeterData
+	logSendCh     chan *logv3.LogData
+
+	segmentWriter *rotateWriter
+	meterWriter   *rotateWriter
+	logWriter     *rotateWriter
+
+	// writeWaitGroup is used to wait for the writing goroutines finished
+	writeWaitGroup sync.WaitGroup
+	closeOnce      sync.Once
+	// pending counts the data queued but not finished writing, for flushing
+	pending reporter.PendingCounter
+
+	// bootFlag is set if Boot be executed
+	bootFlag         bool
+	connectionStatus reporter.ConnectionStatus
+}
+
+func (r *fileReporter) Boot(entity *reporter.Entity, cdsWatchers []reporter.AgentConfigChangeWatcher) {
+	r.entity = entity
+	r.initWritePipeline()
+	r.bootFlag = true
+}
+
+func (r *fileReporter) ConnectionStatus() reporter.ConnectionStatus {
+	return r.connectionStatus
+}
+
+func (r *fileReporter) SendTracing(spans []reporter.ReportedSpan) {
+	segmentObject := reporter.BuildSegmentObject(r.entity, spans)
+	if segmentObject == nil {
+		return
+	}
+	r.pending.Add(1)
+	defer func() {
+		// recover the panic caused by close tracingSendCh
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonClosed, 1)
+			r.logger.Errorf("reporter segment err %v", err)
+		}
+	}()
+	select {
+	case r.tracingSendCh <- segmentObject:
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonQueueFull, 1)
+		r.logger.Errorf("reach max tracing write buffer")
+	}
+}
+
+func (r *fileReporter) SendMetrics(metrics []reporter.ReportedMeter) {
+	meters := reporter.BuildMeterDataList(r.entity, metrics)
+	if len(meters) == 0 {
+		return
+	}
+	// each line should be self-described, so copy the service and timestamp to all meters
+	for _, m := range meters[1:] {
+		m.Service = meters[0].Service
+		m.ServiceInstance = meters[0].ServiceInstance
+		m.Timestamp = meters[0].Timestamp
+	}
+	r.pending.Add(1)
+	defer func() {
+		// recover the panic caused by close metricsSendCh
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonClosed, len(meters))
+			r.logger.Errorf("reporter metrics err %v", err)
+		}
+	}()
+	select {
+	case r.metricsSendCh <- meters:
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonQueueFull, len(meters))
+		r.logger.Errorf("reach max metrics write buffer")
+	}
+}
+
+func (r *fileReporter) SendLog(log *logv3.LogData) {
+	r.pending.Add(1)
+	defer func() {
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonClosed, 1)
+			r.logger.Errorf("reporter log err %v", err)
+		}
+	}()
+	select {
+	case r.logSendCh <- log:
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonQueueFull, 1)
+	}
+}
+
+func (r *fileReporter) QueueDepth(dataType string) int {
+	switch dataType {
+	case reporter.DataTypeSegment:
+		return len(r.tracingSendCh)
+	case reporter.DataTypeMeter:
+		return len(r.metricsSendCh)
+	case reporter.DataTypeLog:
+		return len(r.logSendCh)
+	}
+	return 0
+}
+
+func (r *fileReporter) Close() {
+	if r.bootFlag {
+		r.closeSendChannels()
+		return
+	}
+	r.closeWriters()
+}
+
+func (r *fileReporter) Flush(ctx context.Context) error {
+	if !r.bootFlag {
+		return nil
+	}
+	return reporter.WaitPendingFinished(ctx, &r.pending)
+}
+
+func (r *fileReporter) Shutdown(ctx context.Context) error {
+	if !r.bootFlag {
+		r.closeWriters()
+		return nil
+	}
+	r.closeSendChannels()
+	return reporter.WaitSendingFinished(ctx, &r.writeWaitGroup)
+}
+
+func (r *fileReporter) closeSendChannels() {
+	r.closeOnce.Do(func() {
+		close(r.tracingSendCh)
+		close(r.metricsSendCh)
+		close(r.logSendCh)
+	})
+}
+
+func (r *fileReporter) initWritePipeline() {
+	r.writeWaitGroup.Add(3)
+	go func() {
+		defer r.writeWaitGroup.Done()
+		for s := range r.tracingSendCh {
+			r.writeMessage(r.segmentWriter, s, len(r.tracingSendCh) == 0)
+			r.pending.Done()
+		}
+		r.closeWriter(r.segmentWriter)
+	}()
+	go func() {
+		defer r.writeWaitGroup.Done()
+		for meters := range r.metricsSendCh {
+			for i, m := range meters {
+				r.writeMessage(r.meterWriter, m, i == len(meters)-1 && len(r.metricsSendCh) == 0)
+			}
+			r.pending.Done()
+		}
+		r.closeWriter(r.meterWriter)
+	}()
+	go func() {
+		defer r.writeWaitGroup.Done()
+		for l := range r.logSendCh {
+			r.writeMessage(r.logWriter, l, len(r.logSendCh) == 0)
+			r.pending.Done()
+		}
+		r.closeWriter(r.logWriter)
+	}()
+}
+
+// writeMessage write the message as a JSON line, flush the buffer when no more message is waiting
+func (r *fileReporter) writeMessage(writer *rotateWriter, msg proto.Message, flush bool) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		r.logger.Errorf("marshal the reporting data error %v", err)
+		return
+	}
+	if err := writer.WriteLine(data); err != nil {
+		r.logger.Errorf("write the reporting data into file %s error %v", writer.path, err)
+		return
+	}
+	if !flush {
+		return
+	}
+	if err := writer.Flush(); err != nil {
+		r.logger.Errorf("flush the reporting file %s error %v", writer.path, err)
+	}
+}
+
+func (r *fileReporter) closeWriters() {
+	r.closeWriter(r.segmentWriter)
+	r.closeWriter(r.meterWriter)
+	r.closeWriter(r.logWriter)
+}
+
+func (r *fileReporter) closeWriter(writer *rotateWriter) {
+	if writer == nil {
+		return
+	}
+	if err := writer.Close(); err != nil {
+		r.logger.Errorf("close the reporting file %s error %v", writer.path, err)
+	}
+}
@@ -0,0 +1,54 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package file
+
+import (
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+// FileReporterOption allows for functional options to adjust behavior
+// of a file reporter to be created by NewFileReporter
+type FileReporterOption func(r *fileReporter)
+
+// WithFileMaxSize setup the max bytes size of a single file, the file would be rotated when reached
+func WithFileMaxSize(size int64) FileReporterOption {
+	return func(r *fileReporter) {
+		if size > 0 {
+			r.maxFileSize = size
+		}
+	}
+}
+
+// WithFileMaxBackups setup the max count of rotated files to retain for each data type
+func WithFileMaxBackups(count int) FileReporterOption {
+	return func(r *fileReporter) {
+		if count >= 0 {
+			r.maxBackups = count
+		}
+	}
+}
+
+// WithFileMaxSendQueueSize setup the write queue buffer length
+func WithFileMaxSendQueueSize(maxSendQueueSize int) FileReporterOption {
+	return func(r *fileReporter) {
+		r.tracingSendCh = make(chan *agentv3.SegmentObject, maxSendQueueSize)
+		r.metricsSendCh = make(chan []*agentv3.MeterData, maxSendQueueSize)
+		r.logSendCh = make(chan *logv3.LogData, maxSendQueueSize)
+	}
+}
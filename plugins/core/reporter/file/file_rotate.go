@@ -0,0 +1,122 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// rotateWriter writes lines into the file, the file would be renamed with an index suffix when the size is reached,
+// such as "segments.json" -> "segments.json.1", and the oldest files exceeding the max backups are removed.
+type rotateWriter struct {
+	path        string
+	maxSize     int64
+	maxBackups  int
+	file        *os.File
+	buffer      *bufio.Writer
+	currentSize int64
+}
+
+func newRotateWriter(path string, maxSize int64, maxBackups int) (*rotateWriter, error) {
+	w := &rotateWriter{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteLine append the data and a line separator into the file
+func (w *rotateWriter) WriteLine(data []byte) error {
+	lineSize := int64(len(data)) + 1
+	if w.currentSize > 0 && w.currentSize+lineSize > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err := w.buffer.Write(data); err != nil {
+		return err
+	}
+	if err := w.buffer.WriteByte('\n'); err != nil {
+		return err
+	}
+	w.currentSize += lineSize
+	return nil
+}
+
+func (w *rotateWriter) Flush() error {
+	return w.buffer.Flush()
+}
+
+func (w *rotateWriter) Close() error {
+	if err := w.buffer.Flush(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+func (w *rotateWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file = file
+	w.buffer = bufio.NewWriter(file)
+	w.currentSize = stat.Size()
+	return nil
+}
+
+func (w *rotateWriter) rotate() error {
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return w.open()
+	}
+	// remove the oldest backup, then shift others
+	if err := os.Remove(w.backupPath(w.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(w.backupPath(i), w.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(w.path, w.backupPath(1)); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *rotateWriter) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", w.path, index)
+}
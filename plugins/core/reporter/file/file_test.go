@@ -0,0 +1,198 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package file
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+type testMeter struct {
+	name  string
+	value float64
+}
+
+func (m *testMeter) Name() string              { return m.name }
+func (m *testMeter) Labels() map[string]string { return map[string]string{"key": "value"} }
+func (m *testMeter) Value() float64            { return m.value }
+
+type testSpanContext struct {
+	spanID       int32
+	parentSpanID int32
+}
+
+func (c *testSpanContext) GetTraceID() string                           { return "trace" }
+func (c *testSpanContext) GetSegmentID() string                         { return "segment" }
+func (c *testSpanContext) GetSpanID() int32                             { return c.spanID }
+func (c *testSpanContext) GetParentSpanID() int32                       { return c.parentSpanID }
+func (c *testSpanContext) GetParentSegmentID() string                   { return "" }
+func (c *testSpanContext) GetCorrelationContextValue(key string) string { return "" }
+func (c *testSpanContext) SetCorrelationContextValue(key, value string) {}
+
+type testSpan struct {
+	spanID        int32
+	parentSpanID  int32
+	operationName string
+}
+
+func (s *testSpan) Context() reporter.SegmentContext {
+	return &testSpanContext{spanID: s.spanID, parentSpanID: s.parentSpanID}
+}
+func (s *testSpan) Refs() []reporter.SpanContext { return nil }
+func (s *testSpan) StartTime() int64             { return 1000 }
+func (s *testSpan) EndTime() int64               { return 1001 }
+func (s *testSpan) OperationName() string        { return s.operationName }
+func (s *testSpan) Peer() string                 { return "" }
+func (s *testSpan) SpanType() agentv3.SpanType   { return agentv3.SpanType_Local }
+func (s *testSpan) SpanLayer() agentv3.SpanLayer { return agentv3.SpanLayer_Unknown }
+func (s *testSpan) IsError() bool                { return false }
+func (s *testSpan) Tags() []*commonv3.KeyStringValuePair {
+	return []*commonv3.KeyStringValuePair{{Key: "key", Value: "value"}}
+}
+func (s *testSpan) Logs() []*agentv3.Log { return nil }
+func (s *testSpan) ComponentID() int32   { return 0 }
+
+func TestFileReporter(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewFileReporter(operator.NewTestLogger(), dir)
+	assert.Nil(t, err)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	assert.Equal(t, reporter.ConnectionStatusConnected, r.ConnectionStatus())
+
+	r.SendLog(&logv3.LogData{Service: "service", Endpoint: "/test"})
+	r.SendMetrics([]reporter.ReportedMeter{&testMeter{name: "m1", value: 1}, &testMeter{name: "m2", value: 2}})
+	r.SendTracing(nil)
+	r.SendTracing([]reporter.ReportedSpan{&testSpan{spanID: 1, parentSpanID: 0, operationName: "/exit"},
+		&testSpan{spanID: 0, parentSpanID: -1, operationName: "/entry"}})
+	r.Close()
+
+	assert.Eventually(t, func() bool {
+		return len(readLines(t, filepath.Join(dir, logFileName))) == 1 &&
+			len(readLines(t, filepath.Join(dir, meterFileName))) == 2 &&
+			len(readLines(t, filepath.Join(dir, segmentFileName))) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	logData := &logv3.LogData{}
+	assert.Nil(t, protojson.Unmarshal(readLines(t, filepath.Join(dir, logFileName))[0], logData))
+	assert.Equal(t, "/test", logData.Endpoint)
+
+	for _, line := range readLines(t, filepath.Join(dir, meterFileName)) {
+		meter := &agentv3.MeterData{}
+		assert.Nil(t, protojson.Unmarshal(line, meter))
+		assert.Equal(t, "service", meter.Service)
+		assert.Equal(t, "instance", meter.ServiceInstance)
+	}
+
+	// the empty spans should not be written, so the only line is the segment of the spans
+	segment := &agentv3.SegmentObject{}
+	assert.Nil(t, protojson.Unmarshal(readLines(t, filepath.Join(dir, segmentFileName))[0], segment))
+	assert.Equal(t, "trace", segment.TraceId)
+	assert.Equal(t, "segment", segment.TraceSegmentId)
+	assert.Equal(t, "service", segment.Service)
+	assert.Equal(t, "instance", segment.ServiceInstance)
+	assert.Len(t, segment.Spans, 2)
+	assert.Equal(t, "/exit", segment.Spans[0].OperationName)
+	assert.Equal(t, int32(0), segment.Spans[0].ParentSpanId)
+	assert.Equal(t, "/entry", segment.Spans[1].OperationName)
+	assert.Equal(t, int32(-1), segment.Spans[1].ParentSpanId)
+	assert.Len(t, segment.Spans[1].Tags, 1)
+	assert.Equal(t, "value", segment.Spans[1].Tags[0].Value)
+}
+
+func TestFileReporterShutdown(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewFileReporter(operator.NewTestLogger(), dir)
+	assert.Nil(t, err)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+
+	for i := 0; i < 100; i++ {
+		r.SendLog(&logv3.LogData{Service: "service", Endpoint: "/test"})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, r.Shutdown(ctx))
+	// all the pending data should be written when shutdown returned
+	assert.Len(t, readLines(t, filepath.Join(dir, logFileName)), 100)
+	// close again should not panic
+	r.Close()
+}
+
+func TestFileReporterFlush(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewFileReporter(operator.NewTestLogger(), dir)
+	assert.Nil(t, err)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+
+	for i := 0; i < 100; i++ {
+		r.SendLog(&logv3.LogData{Service: "service", Endpoint: "/test"})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, r.Flush(ctx))
+	// all the queued data should be written when flush returned, not only taken from the queue
+	assert.Len(t, readLines(t, filepath.Join(dir, logFileName)), 100)
+
+	// the reporter keeps working after flushed
+	r.SendLog(&logv3.LogData{Service: "service", Endpoint: "/test"})
+	assert.Nil(t, r.Shutdown(ctx))
+	assert.Len(t, readLines(t, filepath.Join(dir, logFileName)), 101)
+}
+
+func TestRotateWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.json")
+	w, err := newRotateWriter(path, 10, 2)
+	assert.Nil(t, err)
+	for _, line := range []string{"aaaaaa", "bbbbbb", "cccccc", "dddddd"} {
+		assert.Nil(t, w.WriteLine([]byte(line)))
+	}
+	assert.Nil(t, w.Close())
+
+	assert.Equal(t, [][]byte{[]byte("dddddd")}, readLines(t, path))
+	assert.Equal(t, [][]byte{[]byte("cccccc")}, readLines(t, path+".1"))
+	assert.Equal(t, [][]byte{[]byte("bbbbbb")}, readLines(t, path+".2"))
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func readLines(t *testing.T, path string) [][]byte {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open file %s error: %v", path, err)
+	}
+	defer file.Close()
+	var lines [][]byte
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	return lines
+}
@@ -19,7 +19,11 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
@@ -27,6 +31,8 @@ import (
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // register the gzip compressor
 	"google.golang.org/grpc/metadata"
 
 	configuration "skywalking.apache.org/repo/goapi/collect/agent/configuration/v3"
@@ -39,12 +45,15 @@ import (
 )
 
 const (
-	maxSendQueueSize     int32 = 30000
-	defaultCheckInterval       = 20 * time.Second
-	defaultCDSInterval         = 20 * time.Second
+	maxSendQueueSize            int32 = 30000
+	defaultCheckInterval              = 20 * time.Second
+	defaultCDSInterval                = 20 * time.Second
+	defaultSegmentBatchInterval       = time.Second
 )
 
-// NewGRPCReporter create a new reporter to send data to gRPC oap server. Only one backend address is allowed.
+// NewGRPCReporter create a new reporter to send data to gRPC oap server.
+// The server address could be a comma-separated list, or a DNS name resolved to several addresses,
+// the streams are balanced across the healthy backends, and switched to others when the backend failed.
 func NewGRPCReporter(logger operator.LogOperator, serverAddr string, opts ...ReporterOption) (reporter.Reporter, error) {
 	r := &gRPCReporter{
 		logger:           logger,
@@ -58,6 +67,14 @@ func NewGRPCReporter(logger operator.LogOperator, serverAddr string, opts ...Rep
 	for _, o := range opts {
 		o(r)
 	}
+	for _, policy := range []sendQueuePolicy{r.tracingQueuePolicy, r.metricsQueuePolicy, r.logQueuePolicy} {
+		if err := policy.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if r.segmentBatchSize > 1 && r.segmentBatchInterval <= 0 {
+		r.segmentBatchInterval = defaultSegmentBatchInterval
+	}
 
 	var credsDialOption grpc.DialOption
 	if r.creds != nil {
@@ -67,7 +84,17 @@ func NewGRPCReporter(logger operator.LogOperator, serverAddr string, opts ...Rep
 		credsDialOption = grpc.WithTransportCredentials(insecure.NewCredentials())
 	}
 
-	conn, err := grpc.Dial(serverAddr, credsDialOption, grpc.WithConnectParams(grpc.ConnectParams{
+	target, dialOptions, err := buildBackendTarget(serverAddr)
+	if err != nil {
+		return nil, err
+	}
+	if r.compressor != "" {
+		if encoding.GetCompressor(r.compressor) == nil {
+			return nil, fmt.Errorf("the compressor %s of gRPC reporter is not registered", r.compressor)
+		}
+		dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(grpc.UseCompressor(r.compressor)))
+	}
+	dialOptions = append(dialOptions, credsDialOption, grpc.WithConnectParams(grpc.ConnectParams{
 		// update the max backoff delay interval
 		Backoff: backoff.Config{
 			BaseDelay:  1.0 * time.Second,
@@ -76,6 +103,7 @@ func NewGRPCReporter(logger operator.LogOperator, serverAddr string, opts ...Rep
 			MaxDelay:   r.checkInterval,
 		},
 	}))
+	conn, err := grpc.Dial(target, dialOptions...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,10 +116,34 @@ func NewGRPCReporter(logger operator.LogOperator, serverAddr string, opts ...Rep
 		r.cdsClient = configuration.NewConfigurationDiscoveryServiceClient(r.conn)
 		r.cdsService = reporter.NewConfigDiscoveryService()
 	}
+	if r.spoolDir != "" {
+		if err := r.initSpools(); err != nil {
+			return nil, err
+		}
+	}
 	return r, nil
 }
 
+// SpoolEnabled returns true when the spools are initialized
+func (r *gRPCReporter) SpoolEnabled() bool {
+	return r.spoolDir != ""
+}
+
+func (r *gRPCReporter) initSpools() (err error) {
+	if r.tracingSpool, err = newDiskSpool(filepath.Join(r.spoolDir, spoolSegmentDir), r.spoolMaxSize, r.spoolMaxAge); err != nil {
+		return err
+	}
+	if r.metricsSpool, err = newDiskSpool(filepath.Join(r.spoolDir, spoolMeterDir), r.spoolMaxSize, r.spoolMaxAge); err != nil {
+		return err
+	}
+	if r.logSpool, err = newDiskSpool(filepath.Join(r.spoolDir, spoolLogDir), r.spoolMaxSize, r.spoolMaxAge); err != nil {
+		return err
+	}
+	return nil
+}
+
 type gRPCReporter struct {
+	reporter.SelfObserverHolder
 	entity           *reporter.Entity
 	logger           operator.LogOperator
 	tracingSendCh    chan *agentv3.SegmentObject
@@ -110,6 +162,35 @@ type gRPCReporter struct {
 	md    metadata.MD
 	creds credentials.TransportCredentials
 
+	// the policies of sending queues when they are full
+	tracingQueuePolicy sendQueuePolicy
+	metricsQueuePolicy sendQueuePolicy
+	logQueuePolicy     sendQueuePolicy
+
+	// compressor is the name of compressor for all the streams, not compress when it's empty
+	compressor string
+	// segments are sent in batch when the batch size greater than 1
+	segmentBatchSize     int
+	segmentBatchInterval time.Duration
+	// the stream is reopened after sending the count of messages, so the streams are rebalanced across the backends,
+	// the stream is never reopened for rebalancing when it's not greater than 0
+	streamRecycleCount int
+
+	// spools keep the data on the disk when the backend is disconnected
+	spoolDir     string
+	spoolMaxSize int64
+	spoolMaxAge  time.Duration
+	tracingSpool *diskSpool
+	metricsSpool *diskSpool
+	logSpool     *diskSpool
+
+	// sendWaitGroup is used to wait for the sending goroutines finished
+	sendWaitGroup sync.WaitGroup
+	closeOnce     sync.Once
+	closed        int32
+	// pending counts the data queued but not finished sending, for flushing
+	pending reporter.PendingCounter
+
 	// bootFlag is set if Boot be executed
 	bootFlag         bool
 	connectionStatus reporter.ConnectionStatus
@@ -128,165 +209,109 @@ func (r *gRPCReporter) ConnectionStatus() reporter.ConnectionStatus {
 }
 
 func (r *gRPCReporter) SendTracing(spans []reporter.ReportedSpan) {
-	spanSize := len(spans)
-	if spanSize < 1 {
+	segmentObject := reporter.BuildSegmentObject(r.entity, spans)
+	if segmentObject == nil {
 		return
 	}
-	rootSpan := spans[spanSize-1]
-	rootCtx := rootSpan.Context()
-	segmentObject := &agentv3.SegmentObject{
-		TraceId:         rootCtx.GetTraceID(),
-		TraceSegmentId:  rootCtx.GetSegmentID(),
-		Spans:           make([]*agentv3.SpanObject, spanSize),
-		Service:         r.entity.ServiceName,
-		ServiceInstance: r.entity.ServiceInstanceName,
-	}
-	for i, s := range spans {
-		spanCtx := s.Context()
-		segmentObject.Spans[i] = &agentv3.SpanObject{
-			SpanId:        spanCtx.GetSpanID(),
-			ParentSpanId:  spanCtx.GetParentSpanID(),
-			StartTime:     s.StartTime(),
-			EndTime:       s.EndTime(),
-			OperationName: s.OperationName(),
-			Peer:          s.Peer(),
-			SpanType:      s.SpanType(),
-			SpanLayer:     s.SpanLayer(),
-			ComponentId:   s.ComponentID(),
-			IsError:       s.IsError(),
-			Tags:          s.Tags(),
-			Logs:          s.Logs(),
-		}
-		srr := make([]*agentv3.SegmentReference, 0)
-		if i == (spanSize-1) && spanCtx.GetParentSpanID() > -1 {
-			srr = append(srr, &agentv3.SegmentReference{
-				RefType:               agentv3.RefType_CrossThread,
-				TraceId:               spanCtx.GetTraceID(),
-				ParentTraceSegmentId:  spanCtx.GetParentSegmentID(),
-				ParentSpanId:          spanCtx.GetParentSpanID(),
-				ParentService:         r.entity.ServiceName,
-				ParentServiceInstance: r.entity.ServiceInstanceName,
-			})
-		}
-		if len(s.Refs()) > 0 {
-			for _, tc := range s.Refs() {
-				srr = append(srr, &agentv3.SegmentReference{
-					RefType:                  agentv3.RefType_CrossProcess,
-					TraceId:                  spanCtx.GetTraceID(),
-					ParentTraceSegmentId:     tc.GetParentSegmentID(),
-					ParentSpanId:             tc.GetParentSpanID(),
-					ParentService:            tc.GetParentService(),
-					ParentServiceInstance:    tc.GetParentServiceInstance(),
-					ParentEndpoint:           tc.GetParentEndpoint(),
-					NetworkAddressUsedAtPeer: tc.GetAddressUsedAtClient(),
-				})
-			}
-		}
-		segmentObject.Spans[i].Refs = srr
-	}
+	r.pending.Add(1)
 	defer func() {
 		// recover the panic caused by close tracingSendCh
 		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonClosed, 1)
 			r.logger.Errorf("reporter segment err %v", err)
 		}
 	}()
-	select {
-	case r.tracingSendCh <- segmentObject:
-	default:
+	if !r.enqueueSegment(segmentObject) {
+		r.pending.Done()
 		r.logger.Errorf("reach max tracing send buffer")
 	}
 }
 
 func (r *gRPCReporter) SendMetrics(metrics []reporter.ReportedMeter) {
-	if len(metrics) == 0 {
+	meters := reporter.BuildMeterDataList(r.entity, metrics)
+	if len(meters) == 0 {
 		return
 	}
-	meters := make([]*agentv3.MeterData, len(metrics))
-	for i, m := range metrics {
-		meter := &agentv3.MeterData{}
-		switch data := m.(type) {
-		case reporter.ReportedMeterSingleValue:
-			meter.Metric = &agentv3.MeterData_SingleValue{
-				SingleValue: &agentv3.MeterSingleValue{
-					Name:   data.Name(),
-					Labels: r.convertLabels(data.Labels()),
-					Value:  data.Value(),
-				},
-			}
-		case reporter.ReportedMeterHistogram:
-			buckets := make([]*agentv3.MeterBucketValue, len(data.BucketValues()))
-			for i, b := range data.BucketValues() {
-				buckets[i] = &agentv3.MeterBucketValue{
-					Bucket:             b.Bucket(),
-					Count:              b.Count(),
-					IsNegativeInfinity: b.IsNegativeInfinity(),
-				}
-			}
-			meter.Metric = &agentv3.MeterData_Histogram{
-				Histogram: &agentv3.MeterHistogram{
-					Name:   data.Name(),
-					Labels: r.convertLabels(data.Labels()),
-					Values: buckets,
-				},
-			}
-		}
-
-		meters[i] = meter
-	}
-
-	meters[0].Service = r.entity.ServiceName
-	meters[0].ServiceInstance = r.entity.ServiceInstanceName
-	meters[0].Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	r.pending.Add(1)
 	defer func() {
 		// recover the panic caused by close tracingSendCh
 		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonClosed, len(meters))
 			r.logger.Errorf("reporter metrics err %v", err)
 		}
 	}()
-	select {
-	case r.metricsSendCh <- meters:
-	default:
+	if !r.enqueueMeters(meters) {
+		r.pending.Done()
 		r.logger.Errorf("reach max metrics send buffer")
 	}
 }
 
 func (r *gRPCReporter) SendLog(log *logv3.LogData) {
+	r.pending.Add(1)
 	defer func() {
 		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonClosed, 1)
 			r.logger.Errorf("reporter log err %v", err)
 		}
 	}()
-	select {
-	case r.logSendCh <- log:
-	default:
+	if !r.enqueueLog(log) {
+		r.pending.Done()
 	}
 }
 
-func (r *gRPCReporter) convertLabels(labels map[string]string) []*agentv3.Label {
-	if len(labels) == 0 {
-		return nil
+func (r *gRPCReporter) QueueDepth(dataType string) int {
+	switch dataType {
+	case reporter.DataTypeSegment:
+		return len(r.tracingSendCh)
+	case reporter.DataTypeMeter:
+		return len(r.metricsSendCh)
+	case reporter.DataTypeLog:
+		return len(r.logSendCh)
 	}
-	ls := make([]*agentv3.Label, 0)
-	for k, v := range labels {
-		ls = append(ls, &agentv3.Label{
-			Name:  k,
-			Value: v,
-		})
-	}
-	return ls
+	return 0
 }
 
 func (r *gRPCReporter) Close() {
 	if r.bootFlag {
+		r.closeSendChannels()
+	} else {
+		r.closeGRPCConn()
+	}
+}
+
+func (r *gRPCReporter) Flush(ctx context.Context) error {
+	if !r.bootFlag {
+		return nil
+	}
+	return reporter.WaitPendingFinished(ctx, &r.pending)
+}
+
+func (r *gRPCReporter) Shutdown(ctx context.Context) error {
+	if !r.bootFlag {
+		r.closeGRPCConn()
+		return nil
+	}
+	r.closeSendChannels()
+	return reporter.WaitSendingFinished(ctx, &r.sendWaitGroup)
+}
+
+// closeSendChannels closes all the sending channels, the sending goroutines would be finished after all data sent
+func (r *gRPCReporter) closeSendChannels() {
+	r.closeOnce.Do(func() {
+		atomic.StoreInt32(&r.closed, 1)
 		if r.tracingSendCh != nil {
 			close(r.tracingSendCh)
 		}
 		if r.metricsSendCh != nil {
 			close(r.metricsSendCh)
 		}
-	} else {
-		r.closeGRPCConn()
-	}
+		if r.logSendCh != nil {
+			close(r.logSendCh)
+		}
+	})
 }
 
 func (r *gRPCReporter) closeGRPCConn() {
@@ -302,98 +327,221 @@ func (r *gRPCReporter) initSendPipeline() {
 	if r.traceClient == nil {
 		return
 	}
+	r.sendWaitGroup.Add(3)
+	go func() {
+		// close the connection after all the data sent
+		r.sendWaitGroup.Wait()
+		r.closeGRPCConn()
+	}()
 	go func() {
+		defer r.sendWaitGroup.Done()
+		streamOpened := false
 	StreamLoop:
 		for {
 			switch r.updateConnectionStatus() {
 			case reporter.ConnectionStatusShutdown:
 				break
 			case reporter.ConnectionStatusDisconnect:
-				time.Sleep(5 * time.Second)
+				if r.tracingSpool == nil {
+					if atomic.LoadInt32(&r.closed) == 1 {
+						// the data cannot be sent when shutdown
+						return
+					}
+					time.Sleep(5 * time.Second)
+				} else if !r.spoolTracing(5 * time.Second) {
+					r.closeSpool(r.tracingSpool)
+					return
+				}
 				continue StreamLoop
 			}
 
+			if err := r.drainTracingSpool(); err != nil {
+				r.logger.Errorf("send spooled segment error %v", err)
+				time.Sleep(5 * time.Second)
+				continue StreamLoop
+			}
+			if r.segmentBatchSize > 1 {
+				// the segments are sent in sync requests when sending in batch, no stream is opened
+				if r.sendSegmentBatches(streamOpened) {
+					streamOpened = true
+					continue StreamLoop
+				}
+				r.closeSpool(r.tracingSpool)
+				break
+			}
 			stream, err := r.traceClient.Collect(metadata.NewOutgoingContext(context.Background(), r.md))
 			if err != nil {
 				r.logger.Errorf("open stream error %v", err)
 				time.Sleep(5 * time.Second)
 				continue StreamLoop
 			}
+			if streamOpened {
+				r.ObserveReconnected(reporter.DataTypeSegment)
+			}
+			streamOpened = true
+			sent := 0
 			for s := range r.tracingSendCh {
+				start := time.Now()
 				err = stream.Send(s)
 				if err != nil {
 					r.logger.Errorf("send segment error %v", err)
+					r.spoolFailedData(r.tracingSpool, reporter.DataTypeSegment, s, 1)
+					r.pending.Done()
 					r.closeTracingStream(stream)
 					continue StreamLoop
 				}
+				r.ObserveSent(reporter.DataTypeSegment, start)
+				r.pending.Done()
+				sent++
+				if r.shouldRecycleStream(sent) {
+					r.closeTracingStream(stream)
+					// not a reconnection, the stream is reopened for rebalancing
+					streamOpened = false
+					continue StreamLoop
+				}
 			}
 			r.closeTracingStream(stream)
-			r.closeGRPCConn()
+			r.closeSpool(r.tracingSpool)
 			break
 		}
 	}()
 	go func() {
+		defer r.sendWaitGroup.Done()
+		streamOpened := false
 	StreamLoop:
 		for {
 			switch r.updateConnectionStatus() {
 			case reporter.ConnectionStatusShutdown:
 				break
 			case reporter.ConnectionStatusDisconnect:
-				time.Sleep(5 * time.Second)
+				if r.metricsSpool == nil {
+					if atomic.LoadInt32(&r.closed) == 1 {
+						// the data cannot be sent when shutdown
+						return
+					}
+					time.Sleep(5 * time.Second)
+				} else if !r.spoolMetrics(5 * time.Second) {
+					r.closeSpool(r.metricsSpool)
+					return
+				}
 				continue StreamLoop
 			}
 
+			if err := r.drainMetricsSpool(); err != nil {
+				r.logger.Errorf("send spooled metrics error %v", err)
+				time.Sleep(5 * time.Second)
+				continue StreamLoop
+			}
 			stream, err := r.metricsClient.CollectBatch(metadata.NewOutgoingContext(context.Background(), r.md))
 			if err != nil {
 				r.logger.Errorf("open stream error %v", err)
 				time.Sleep(5 * time.Second)
 				continue StreamLoop
 			}
+			if streamOpened {
+				r.ObserveReconnected(reporter.DataTypeMeter)
+			}
+			streamOpened = true
+			sent := 0
 			for s := range r.metricsSendCh {
-				err = stream.Send(&agentv3.MeterDataCollection{
+				collection := &agentv3.MeterDataCollection{
 					MeterData: s,
-				})
+				}
+				start := time.Now()
+				err = stream.Send(collection)
 				if err != nil {
 					r.logger.Errorf("send metrics error %v", err)
+					r.spoolFailedData(r.metricsSpool, reporter.DataTypeMeter, collection, len(s))
+					r.pending.Done()
+					r.closeMetricsStream(stream)
+					continue StreamLoop
+				}
+				r.ObserveSent(reporter.DataTypeMeter, start)
+				r.pending.Done()
+				sent++
+				if r.shouldRecycleStream(sent) {
 					r.closeMetricsStream(stream)
+					// not a reconnection, the stream is reopened for rebalancing
+					streamOpened = false
 					continue StreamLoop
 				}
 			}
 			r.closeMetricsStream(stream)
+			r.closeSpool(r.metricsSpool)
 			break
 		}
 	}()
 	go func() {
+		defer r.sendWaitGroup.Done()
+		streamOpened := false
 	StreamLoop:
 		for {
 			switch r.updateConnectionStatus() {
 			case reporter.ConnectionStatusShutdown:
 				break
 			case reporter.ConnectionStatusDisconnect:
-				time.Sleep(5 * time.Second)
+				if r.logSpool == nil {
+					if atomic.LoadInt32(&r.closed) == 1 {
+						// the data cannot be sent when shutdown
+						return
+					}
+					time.Sleep(5 * time.Second)
+				} else if !r.spoolLogs(5 * time.Second) {
+					r.closeSpool(r.logSpool)
+					return
+				}
 				continue StreamLoop
 			}
 
+			if err := r.drainLogSpool(); err != nil {
+				r.logger.Errorf("send spooled log error %v", err)
+				time.Sleep(5 * time.Second)
+				continue StreamLoop
+			}
 			stream, err := r.logClient.Collect(metadata.NewOutgoingContext(context.Background(), r.md))
 			if err != nil {
 				r.logger.Errorf("open stream error %v", err)
 				time.Sleep(5 * time.Second)
 				continue StreamLoop
 			}
+			if streamOpened {
+				r.ObserveReconnected(reporter.DataTypeLog)
+			}
+			streamOpened = true
+			sent := 0
 			for s := range r.logSendCh {
+				start := time.Now()
 				err = stream.Send(s)
 				if err != nil {
 					r.logger.Errorf("send log error %v", err)
+					r.spoolFailedData(r.logSpool, reporter.DataTypeLog, s, 1)
+					r.pending.Done()
+					r.closeLogStream(stream)
+					continue StreamLoop
+				}
+				r.ObserveSent(reporter.DataTypeLog, start)
+				r.pending.Done()
+				sent++
+				if r.shouldRecycleStream(sent) {
 					r.closeLogStream(stream)
+					// not a reconnection, the stream is reopened for rebalancing
+					streamOpened = false
 					continue StreamLoop
 				}
 			}
 			r.closeLogStream(stream)
+			r.closeSpool(r.logSpool)
 			break
 		}
 	}()
 }
 
+// shouldRecycleStream checks the stream should be reopened after sent the count of messages,
+// the balancer only picks the backend when the stream opened, so the long-lived stream would stick to one backend
+func (r *gRPCReporter) shouldRecycleStream(sent int) bool {
+	return r.streamRecycleCount > 0 && sent >= r.streamRecycleCount
+}
+
 func (r *gRPCReporter) updateConnectionStatus() reporter.ConnectionStatus {
 	state := r.conn.GetState()
 	switch state {
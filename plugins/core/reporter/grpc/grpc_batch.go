@@ -0,0 +1,83 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+// sendSegmentBatches collects the segments into batches, the batch is sent when reaching the size or the interval.
+// When reconnecting after the previous sending failure, the reconnection is observed after the first batch sent.
+// Returns true when sending failure, or false when the sending channel is closed.
+func (r *gRPCReporter) sendSegmentBatches(reconnecting bool) bool {
+	ticker := time.NewTicker(r.segmentBatchInterval)
+	defer ticker.Stop()
+	batch := make([]*agentv3.SegmentObject, 0, r.segmentBatchSize)
+	for {
+		select {
+		case s, ok := <-r.tracingSendCh:
+			if !ok {
+				r.sendSegmentBatch(batch)
+				return false
+			}
+			batch = append(batch, s)
+			if len(batch) < r.segmentBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if err := r.sendSegmentBatch(batch); err != nil {
+			return true
+		}
+		if reconnecting {
+			r.ObserveReconnected(reporter.DataTypeSegment)
+			reconnecting = false
+		}
+		batch = make([]*agentv3.SegmentObject, 0, r.segmentBatchSize)
+	}
+}
+
+// sendSegmentBatch sends the segments in one request, the segments are spooled or dropped when sending failure
+func (r *gRPCReporter) sendSegmentBatch(batch []*agentv3.SegmentObject) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	defer r.pending.Add(-len(batch))
+	start := time.Now()
+	_, err := r.traceClient.CollectInSync(metadata.NewOutgoingContext(context.Background(), r.md),
+		&agentv3.SegmentCollection{Segments: batch})
+	if err != nil {
+		r.logger.Errorf("send segment batch error %v", err)
+		for _, s := range batch {
+			r.spoolFailedData(r.tracingSpool, reporter.DataTypeSegment, s, 1)
+		}
+		return err
+	}
+	r.ObserveSent(reporter.DataTypeSegment, start)
+	return nil
+}
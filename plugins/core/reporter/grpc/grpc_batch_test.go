@@ -0,0 +1,57 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+func TestGRPCReporterSegmentBatch(t *testing.T) {
+	server := startTestSegmentServer(t)
+	defer server.server.Stop()
+	rep, err := NewGRPCReporter(operator.NewTestLogger(), server.addr, WithCheckInterval(time.Second), WithCDS(-1),
+		WithCompressor("gzip"), WithSegmentBatch(3, 50*time.Millisecond))
+	assert.Nil(t, err)
+	r := rep.(*gRPCReporter)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	defer r.Close()
+
+	// the first batch is sent by reaching the size, and the second one by the interval
+	for i := 0; i < 4; i++ {
+		r.tracingSendCh <- &agentv3.SegmentObject{TraceSegmentId: "segment"}
+	}
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&server.received) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&server.batches))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&server.streams), "the stream should not be opened when sending in batch")
+}
+
+func TestGRPCReporterUnknownCompressor(t *testing.T) {
+	_, err := NewGRPCReporter(operator.NewTestLogger(), "127.0.0.1:11800", WithCompressor("unknown"))
+	assert.NotNil(t, err)
+}
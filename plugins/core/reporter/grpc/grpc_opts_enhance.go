@@ -18,10 +18,6 @@
 package grpc
 
 import (
-	"crypto/tls"
-	"crypto/x509"
-	"fmt"
-	"os"
 	"time"
 
 	"google.golang.org/grpc/credentials"
@@ -29,6 +25,8 @@ import (
 
 	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
 	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
 )
 
 var authKey = "Authentication"
@@ -53,6 +51,33 @@ func WithMaxSendQueueSize(maxSendQueueSize int) ReporterOption {
 	}
 }
 
+// WithTracingSendQueue setup the size and drop policy of the segment sending queue,
+// the block timeout only works with the BlockWithTimeout policy
+func WithTracingSendQueue(size int, policy DropPolicy, blockTimeout time.Duration) ReporterOption {
+	return func(r *gRPCReporter) {
+		r.tracingSendCh = make(chan *agentv3.SegmentObject, size)
+		r.tracingQueuePolicy = sendQueuePolicy{policy: policy, blockTimeout: blockTimeout}
+	}
+}
+
+// WithMetricsSendQueue setup the size and drop policy of the meter sending queue,
+// the block timeout only works with the BlockWithTimeout policy
+func WithMetricsSendQueue(size int, policy DropPolicy, blockTimeout time.Duration) ReporterOption {
+	return func(r *gRPCReporter) {
+		r.metricsSendCh = make(chan []*agentv3.MeterData, size)
+		r.metricsQueuePolicy = sendQueuePolicy{policy: policy, blockTimeout: blockTimeout}
+	}
+}
+
+// WithLogSendQueue setup the size and drop policy of the log sending queue,
+// the block timeout only works with the BlockWithTimeout policy
+func WithLogSendQueue(size int, policy DropPolicy, blockTimeout time.Duration) ReporterOption {
+	return func(r *gRPCReporter) {
+		r.logSendCh = make(chan *logv3.LogData, size)
+		r.logQueuePolicy = sendQueuePolicy{policy: policy, blockTimeout: blockTimeout}
+	}
+}
+
 // WithTransportCredentials setup transport layer security
 func WithTransportCredentials(creds credentials.TransportCredentials) ReporterOption {
 	return func(r *gRPCReporter) {
@@ -67,6 +92,40 @@ func WithAuthentication(auth string) ReporterOption {
 	}
 }
 
+// WithSpool setup the disk spool to keep the data when the backend is disconnected,
+// the data in each spool is limited by the max size(bytes) and max age, the spool is disabled when the dir is empty
+func WithSpool(dir string, maxSize int64, maxAge time.Duration) ReporterOption {
+	return func(r *gRPCReporter) {
+		r.spoolDir = dir
+		r.spoolMaxSize = maxSize
+		r.spoolMaxAge = maxAge
+	}
+}
+
+// WithCompressor setup the compressor of all the streams, such as "gzip", the compressor must be registered in gRPC
+func WithCompressor(name string) ReporterOption {
+	return func(r *gRPCReporter) {
+		r.compressor = name
+	}
+}
+
+// WithSegmentBatch setup sending the segments in batch, the batch is sent when reaching the size or the interval,
+// the batching is disabled when the size is not greater than 1
+func WithSegmentBatch(size int, interval time.Duration) ReporterOption {
+	return func(r *gRPCReporter) {
+		r.segmentBatchSize = size
+		r.segmentBatchInterval = interval
+	}
+}
+
+// WithStreamRecycle setup reopening the stream after sending the count of messages, so the streams are rebalanced
+// across the backends, the stream is never reopened for rebalancing when the count is not greater than 0
+func WithStreamRecycle(count int) ReporterOption {
+	return func(r *gRPCReporter) {
+		r.streamRecycleCount = count
+	}
+}
+
 // WithCDS setup Configuration Discovery Service to dynamic config
 func WithCDS(interval time.Duration) ReporterOption {
 	return func(r *gRPCReporter) {
@@ -76,50 +135,9 @@ func WithCDS(interval time.Duration) ReporterOption {
 
 //nolint
 func generateTLSCredential(caPath, clientKeyPath, clientCertChainPath string, skipVerify bool) (tc credentials.TransportCredentials, tlsErr error) {
-	if err := checkTLSFile(caPath); err != nil {
-		return nil, err
-	}
-	tlsConfig := new(tls.Config)
-	tlsConfig.Renegotiation = tls.RenegotiateNever
-	tlsConfig.InsecureSkipVerify = skipVerify
-	caPem, err := os.ReadFile(caPath)
+	tlsConfig, err := reporter.NewTLSConfig(caPath, clientKeyPath, clientCertChainPath, skipVerify)
 	if err != nil {
 		return nil, err
 	}
-	certPool := x509.NewCertPool()
-	if !certPool.AppendCertsFromPEM(caPem) {
-		return nil, fmt.Errorf("failed to append certificates")
-	}
-	tlsConfig.RootCAs = certPool
-
-	if clientKeyPath != "" && clientCertChainPath != "" {
-		if err := checkTLSFile(clientKeyPath); err != nil {
-			return nil, err
-		}
-		if err := checkTLSFile(clientCertChainPath); err != nil {
-			return nil, err
-		}
-		clientPem, err := tls.LoadX509KeyPair(clientCertChainPath, clientKeyPath)
-		if err != nil {
-			return nil, err
-		}
-		tlsConfig.Certificates = []tls.Certificate{clientPem}
-	}
 	return credentials.NewTLS(tlsConfig), nil
 }
-
-// checkTLSFile checks the TLS files.
-func checkTLSFile(path string) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	stat, err := file.Stat()
-	if err != nil {
-		return err
-	}
-	if stat.Size() == 0 {
-		return fmt.Errorf("the TLS file is illegal: %s", path)
-	}
-	return nil
-}
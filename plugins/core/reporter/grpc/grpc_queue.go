@@ -0,0 +1,166 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"time"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+// DropPolicy decides which data is dropped when the sending queue is full.
+type DropPolicy string
+
+const (
+	// DropNewest drops the new data when the queue is full
+	DropNewest DropPolicy = "drop_newest"
+	// DropOldest evicts the oldest data in the queue to keep the new one
+	DropOldest DropPolicy = "drop_oldest"
+	// BlockWithTimeout waits for the free space of the queue, drops the new data when timeout
+	BlockWithTimeout DropPolicy = "block"
+)
+
+// the max times of evicting the oldest data, the queue could be filled by others concurrently
+const maxEvictTimes = 3
+
+type sendQueuePolicy struct {
+	policy       DropPolicy
+	blockTimeout time.Duration
+}
+
+func (p sendQueuePolicy) validate() error {
+	switch p.policy {
+	case "", DropNewest, DropOldest:
+		return nil
+	case BlockWithTimeout:
+		if p.blockTimeout <= 0 {
+			return fmt.Errorf("the block timeout of the sending queue must be positive")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown drop policy of the sending queue: %s", p.policy)
+}
+
+func (r *gRPCReporter) enqueueSegment(segment *agentv3.SegmentObject) bool {
+	switch r.tracingQueuePolicy.policy {
+	case DropOldest:
+		for i := 0; i < maxEvictTimes; i++ {
+			select {
+			case r.tracingSendCh <- segment:
+				return true
+			default:
+			}
+			select {
+			case <-r.tracingSendCh:
+				r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonQueueFull, 1)
+				r.pending.Done()
+			default:
+			}
+		}
+	case BlockWithTimeout:
+		timer := time.NewTimer(r.tracingQueuePolicy.blockTimeout)
+		defer timer.Stop()
+		select {
+		case r.tracingSendCh <- segment:
+			return true
+		case <-timer.C:
+		}
+	default:
+		select {
+		case r.tracingSendCh <- segment:
+			return true
+		default:
+		}
+	}
+	r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonQueueFull, 1)
+	return false
+}
+
+func (r *gRPCReporter) enqueueMeters(meters []*agentv3.MeterData) bool {
+	switch r.metricsQueuePolicy.policy {
+	case DropOldest:
+		for i := 0; i < maxEvictTimes; i++ {
+			select {
+			case r.metricsSendCh <- meters:
+				return true
+			default:
+			}
+			select {
+			case evicted := <-r.metricsSendCh:
+				r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonQueueFull, len(evicted))
+				r.pending.Done()
+			default:
+			}
+		}
+	case BlockWithTimeout:
+		timer := time.NewTimer(r.metricsQueuePolicy.blockTimeout)
+		defer timer.Stop()
+		select {
+		case r.metricsSendCh <- meters:
+			return true
+		case <-timer.C:
+		}
+	default:
+		select {
+		case r.metricsSendCh <- meters:
+			return true
+		default:
+		}
+	}
+	r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonQueueFull, len(meters))
+	return false
+}
+
+func (r *gRPCReporter) enqueueLog(log *logv3.LogData) bool {
+	switch r.logQueuePolicy.policy {
+	case DropOldest:
+		for i := 0; i < maxEvictTimes; i++ {
+			select {
+			case r.logSendCh <- log:
+				return true
+			default:
+			}
+			select {
+			case <-r.logSendCh:
+				r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonQueueFull, 1)
+				r.pending.Done()
+			default:
+			}
+		}
+	case BlockWithTimeout:
+		timer := time.NewTimer(r.logQueuePolicy.blockTimeout)
+		defer timer.Stop()
+		select {
+		case r.logSendCh <- log:
+			return true
+		case <-timer.C:
+		}
+	default:
+		select {
+		case r.logSendCh <- log:
+			return true
+		default:
+		}
+	}
+	r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonQueueFull, 1)
+	return false
+}
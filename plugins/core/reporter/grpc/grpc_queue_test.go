@@ -0,0 +1,61 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+)
+
+func TestGRPCReporterSendQueuePolicy(t *testing.T) {
+	rep, err := NewGRPCReporter(operator.NewTestLogger(), "127.0.0.1:11800", WithCDS(-1),
+		WithTracingSendQueue(2, DropOldest, 0), WithLogSendQueue(1, BlockWithTimeout, 50*time.Millisecond))
+	assert.Nil(t, err)
+	r := rep.(*gRPCReporter)
+	defer r.conn.Close()
+
+	// the oldest segment is evicted to keep the newest one
+	for _, id := range []string{"1", "2", "3"} {
+		assert.True(t, r.enqueueSegment(&agentv3.SegmentObject{TraceSegmentId: id}))
+	}
+	assert.Equal(t, "2", (<-r.tracingSendCh).TraceSegmentId)
+	assert.Equal(t, "3", (<-r.tracingSendCh).TraceSegmentId)
+
+	// the log is dropped after waiting for the timeout
+	assert.True(t, r.enqueueLog(nil))
+	start := time.Now()
+	assert.False(t, r.enqueueLog(nil))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	// the meters are dropped immediately by the default policy
+	r.metricsSendCh = make(chan []*agentv3.MeterData)
+	assert.False(t, r.enqueueMeters(nil))
+}
+
+func TestGRPCReporterUnknownDropPolicy(t *testing.T) {
+	_, err := NewGRPCReporter(operator.NewTestLogger(), "127.0.0.1:11800", WithMetricsSendQueue(10, "unknown", 0))
+	assert.NotNil(t, err)
+	_, err = NewGRPCReporter(operator.NewTestLogger(), "127.0.0.1:11800", WithMetricsSendQueue(10, BlockWithTimeout, 0))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,60 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+const (
+	backendResolverScheme = "skywalking"
+	backendResolverTarget = backendResolverScheme + ":///oap"
+	// the streams are balanced to the ready backends, and the failed backends are skipped
+	roundRobinServiceConfig = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+)
+
+// buildBackendTarget builds the dial target and options of the backend addresses.
+// The addresses could be a comma-separated list, or a DNS name which resolved to several addresses.
+func buildBackendTarget(serverAddr string) (string, []grpc.DialOption, error) {
+	addresses := make([]resolver.Address, 0)
+	for _, addr := range strings.Split(serverAddr, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addresses = append(addresses, resolver.Address{Addr: addr})
+		}
+	}
+	balancerOption := grpc.WithDefaultServiceConfig(roundRobinServiceConfig)
+	switch len(addresses) {
+	case 0:
+		return "", nil, fmt.Errorf("the backend address of the gRPC reporter is empty")
+	case 1:
+		target := addresses[0].Addr
+		if !strings.Contains(target, "://") {
+			// resolve all the addresses of the DNS name
+			target = "dns:///" + target
+		}
+		return target, []grpc.DialOption{balancerOption}, nil
+	}
+	builder := manual.NewBuilderWithScheme(backendResolverScheme)
+	builder.InitialState(resolver.State{Addresses: addresses})
+	return backendResolverTarget, []grpc.DialOption{grpc.WithResolvers(builder), balancerOption}, nil
+}
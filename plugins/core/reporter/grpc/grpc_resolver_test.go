@@ -0,0 +1,145 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+type testSegmentServer struct {
+	agentv3.UnimplementedTraceSegmentReportServiceServer
+	server   *grpc.Server
+	addr     string
+	received int32
+	batches  int32
+	streams  int32
+}
+
+func (s *testSegmentServer) Collect(stream agentv3.TraceSegmentReportService_CollectServer) error {
+	atomic.AddInt32(&s.streams, 1)
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if err == io.EOF {
+				return stream.SendAndClose(&commonv3.Commands{})
+			}
+			return err
+		}
+		atomic.AddInt32(&s.received, 1)
+	}
+}
+
+func (s *testSegmentServer) CollectInSync(ctx context.Context, segments *agentv3.SegmentCollection) (*commonv3.Commands, error) {
+	atomic.AddInt32(&s.batches, 1)
+	atomic.AddInt32(&s.received, int32(len(segments.Segments)))
+	return &commonv3.Commands{}, nil
+}
+
+func startTestSegmentServer(t *testing.T) *testSegmentServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	s := &testSegmentServer{server: grpc.NewServer(), addr: listener.Addr().String()}
+	agentv3.RegisterTraceSegmentReportServiceServer(s.server, s)
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	return s
+}
+
+func TestBuildBackendTarget(t *testing.T) {
+	target, opts, err := buildBackendTarget("oap:11800")
+	assert.Nil(t, err)
+	assert.Equal(t, "dns:///oap:11800", target)
+	assert.Len(t, opts, 1)
+
+	target, _, err = buildBackendTarget("passthrough:///oap:11800")
+	assert.Nil(t, err)
+	assert.Equal(t, "passthrough:///oap:11800", target)
+
+	target, opts, err = buildBackendTarget("oap1:11800, oap2:11800,")
+	assert.Nil(t, err)
+	assert.Equal(t, backendResolverTarget, target)
+	assert.Len(t, opts, 2)
+
+	_, _, err = buildBackendTarget(" , ")
+	assert.NotNil(t, err)
+}
+
+func TestGRPCReporterFailover(t *testing.T) {
+	servers := []*testSegmentServer{startTestSegmentServer(t), startTestSegmentServer(t)}
+	defer func() {
+		for _, s := range servers {
+			s.server.Stop()
+		}
+	}()
+	rep, err := NewGRPCReporter(operator.NewTestLogger(), servers[0].addr+","+servers[1].addr, WithCheckInterval(time.Second), WithCDS(-1))
+	assert.Nil(t, err)
+	r := rep.(*gRPCReporter)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	defer r.Close()
+
+	r.tracingSendCh <- &agentv3.SegmentObject{TraceSegmentId: "segment"}
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&servers[0].received)+atomic.LoadInt32(&servers[1].received) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// stop the backend which received the segment, the segments should be sent to another one
+	failed, alive := servers[0], servers[1]
+	if atomic.LoadInt32(&failed.received) == 0 {
+		failed, alive = alive, failed
+	}
+	failed.server.Stop()
+	assert.Eventually(t, func() bool {
+		r.tracingSendCh <- &agentv3.SegmentObject{TraceSegmentId: "segment"}
+		return atomic.LoadInt32(&alive.received) > 0
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestGRPCReporterStreamBalance(t *testing.T) {
+	servers := []*testSegmentServer{startTestSegmentServer(t), startTestSegmentServer(t)}
+	defer func() {
+		for _, s := range servers {
+			s.server.Stop()
+		}
+	}()
+	rep, err := NewGRPCReporter(operator.NewTestLogger(), servers[0].addr+","+servers[1].addr, WithCheckInterval(time.Second), WithCDS(-1),
+		WithStreamRecycle(2))
+	assert.Nil(t, err)
+	r := rep.(*gRPCReporter)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	defer r.Close()
+
+	// the stream is reopened after every 2 segments, and the reopened streams are balanced across the backends
+	assert.Eventually(t, func() bool {
+		r.tracingSendCh <- &agentv3.SegmentObject{TraceSegmentId: "segment"}
+		return atomic.LoadInt32(&servers[0].received) > 0 && atomic.LoadInt32(&servers[1].received) > 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
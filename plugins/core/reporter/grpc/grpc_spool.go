@@ -0,0 +1,584 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+const (
+	spoolSegmentDir      = "segments"
+	spoolMeterDir        = "meters"
+	spoolLogDir          = "logs"
+	spoolChunkSuffix     = ".spool"
+	spoolOffsetSuffix    = ".offset"
+	spoolOffsetLen       = 8
+	spoolChunkCount      = 10
+	spoolRecordHeaderLen = 8
+	spoolCommitRecords   = 100
+)
+
+// diskSpool is a bounded queue persisted in the local directory, the data is stored in ordered chunk files.
+// Each record has a header of the data length and the count of reported data in it, such as the meters in a collection.
+// The oldest chunk would be dropped when the total size reaches the max size, or the chunk is not modified longer than the max age.
+type diskSpool struct {
+	dir       string
+	maxSize   int64
+	maxAge    time.Duration
+	chunkSize int64
+	// commitRecords is the count of records sent before committing them
+	commitRecords int
+
+	lock   sync.Mutex
+	chunks []*spoolChunk
+	size   int64
+	seq    uint64
+	writer *os.File
+}
+
+type spoolChunk struct {
+	path     string
+	size     int64
+	modified time.Time
+	// offset is the size of the records which have already been drained and committed,
+	// it's persisted in the offset file, so the committed records would not be sent again by the next process
+	offset int64
+	// count is the count of the reported data in the records after the offset
+	count int
+}
+
+func (c *spoolChunk) offsetPath() string {
+	return strings.TrimSuffix(c.path, spoolChunkSuffix) + spoolOffsetSuffix
+}
+
+func newDiskSpool(dir string, maxSize int64, maxAge time.Duration) (*diskSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &diskSpool{
+		dir:       dir,
+		maxSize:   maxSize,
+		maxAge:    maxAge,
+		chunkSize: maxSize / spoolChunkCount,
+
+		commitRecords: spoolCommitRecords,
+	}
+	if s.chunkSize <= 0 {
+		s.chunkSize = maxSize
+	}
+	// load the chunks which are left by the previous process
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	offsetFiles := make(map[string]bool)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), spoolOffsetSuffix) {
+			offsetFiles[filepath.Join(dir, entry.Name())] = true
+			continue
+		}
+		seq, ok := parseSpoolChunkSeq(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		chunk := &spoolChunk{path: filepath.Join(dir, entry.Name()), size: info.Size(), modified: info.ModTime()}
+		chunk.offset = readSpoolOffset(chunk.offsetPath(), chunk.size)
+		chunk.count = countSpoolData(chunk.path, chunk.offset)
+		delete(offsetFiles, chunk.offsetPath())
+		s.chunks = append(s.chunks, chunk)
+		s.size += info.Size()
+		if seq > s.seq {
+			s.seq = seq
+		}
+	}
+	// the offset files of the removed chunks, such as the process is killed when removing
+	for path := range offsetFiles {
+		_ = os.Remove(path)
+	}
+	sort.Slice(s.chunks, func(i, j int) bool {
+		first, _ := parseSpoolChunkSeq(filepath.Base(s.chunks[i].path))
+		second, _ := parseSpoolChunkSeq(filepath.Base(s.chunks[j].path))
+		return first < second
+	})
+	return s, nil
+}
+
+// Write appends the data which contains the count of reported data to the newest chunk,
+// returns the count of reported data in the oldest chunks which are dropped for keeping the size limitation
+func (s *diskSpool) Write(data []byte, count int) (dropped int, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.writer == nil || s.chunks[len(s.chunks)-1].size >= s.chunkSize {
+		if err = s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	record := make([]byte, spoolRecordHeaderLen+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:], uint32(count))
+	copy(record[spoolRecordHeaderLen:], data)
+	n, err := s.writer.Write(record)
+	current := s.chunks[len(s.chunks)-1]
+	current.size += int64(n)
+	current.modified = time.Now()
+	s.size += int64(n)
+	if err != nil {
+		return 0, err
+	}
+	current.count += count
+	for s.size > s.maxSize && len(s.chunks) > 1 {
+		dropped += s.removeOldest()
+	}
+	return dropped, nil
+}
+
+// Drain sends all the records from the oldest chunk in order, the sending is committed after every commit records
+// and at the end of every chunk. The drained offset is persisted and the chunk is removed only after the commit succeeded.
+// When sending or committing failure, the records which are not committed would be sent again in the next draining,
+// so the spooled data is delivered at least once, and may be duplicated.
+// The count of reported data in the chunks which are expired is returned as expired,
+// and in the chunk which cannot be read is returned as dropped.
+func (s *diskSpool) Drain(send func(data []byte) error, commit func() error) (expired, dropped int, err error) {
+	for {
+		chunk, count := s.oldestChunk()
+		expired += count
+		if chunk == nil {
+			return expired, 0, nil
+		}
+		content, err := os.ReadFile(chunk.path)
+		if err != nil {
+			s.lock.Lock()
+			dropped = s.removeOldest()
+			s.lock.Unlock()
+			return expired, dropped, fmt.Errorf("read spool chunk %s error: %v", chunk.path, err)
+		}
+		if err := s.drainChunk(chunk, content, send, commit); err != nil {
+			return expired, 0, err
+		}
+		s.lock.Lock()
+		if len(s.chunks) > 0 && s.chunks[0] == chunk {
+			s.removeOldest()
+		}
+		s.lock.Unlock()
+	}
+}
+
+// drainChunk sends the records after the drained offset, and persists the offset after the sent records committed
+func (s *diskSpool) drainChunk(chunk *spoolChunk, content []byte, send func(data []byte) error, commit func() error) error {
+	offsetFile, err := os.OpenFile(chunk.offsetPath(), os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open spool offset %s error: %v", chunk.offsetPath(), err)
+	}
+	defer offsetFile.Close()
+	buf := make([]byte, spoolOffsetLen)
+	offset, uncommitted, uncommittedCount := chunk.offset, 0, 0
+	for offset+spoolRecordHeaderLen <= int64(len(content)) {
+		length := int64(binary.BigEndian.Uint32(content[offset:]))
+		count := int(binary.BigEndian.Uint32(content[offset+4:]))
+		end := offset + spoolRecordHeaderLen + length
+		if end > int64(len(content)) {
+			// the record is broken, such as the process is killed when writing
+			break
+		}
+		if err := send(content[offset+spoolRecordHeaderLen : end]); err != nil {
+			return err
+		}
+		offset = end
+		uncommitted++
+		uncommittedCount += count
+		if uncommitted < s.commitRecords {
+			continue
+		}
+		if err := s.commitOffset(offsetFile, buf, chunk, offset, uncommittedCount, commit); err != nil {
+			return err
+		}
+		uncommitted, uncommittedCount = 0, 0
+	}
+	if uncommitted == 0 {
+		return nil
+	}
+	return s.commitOffset(offsetFile, buf, chunk, offset, uncommittedCount, commit)
+}
+
+// commitOffset commits the sent records, the count is the count of reported data in the committed records
+func (s *diskSpool) commitOffset(offsetFile *os.File, buf []byte, chunk *spoolChunk, offset int64, count int,
+	commit func() error) error {
+	if err := commit(); err != nil {
+		return err
+	}
+	s.lock.Lock()
+	chunk.offset = offset
+	chunk.count -= count
+	s.lock.Unlock()
+	binary.BigEndian.PutUint64(buf, uint64(offset))
+	if _, err := offsetFile.WriteAt(buf, 0); err != nil {
+		return fmt.Errorf("write spool offset %s error: %v", chunk.offsetPath(), err)
+	}
+	return nil
+}
+
+// readSpoolOffset reads the drained offset of the chunk, the chunk is drained from the beginning when the offset is invalid
+func readSpoolOffset(path string, chunkSize int64) int64 {
+	content, err := os.ReadFile(path)
+	if err != nil || len(content) != spoolOffsetLen {
+		return 0
+	}
+	offset := int64(binary.BigEndian.Uint64(content))
+	if offset < 0 || offset > chunkSize {
+		return 0
+	}
+	return offset
+}
+
+// countSpoolData counts the reported data in the complete records after the offset of the chunk
+func countSpoolData(path string, offset int64) int {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for offset+spoolRecordHeaderLen <= int64(len(content)) {
+		recordCount := int(binary.BigEndian.Uint32(content[offset+4:]))
+		offset += spoolRecordHeaderLen + int64(binary.BigEndian.Uint32(content[offset:]))
+		if offset > int64(len(content)) {
+			break
+		}
+		count += recordCount
+	}
+	return count
+}
+
+// IsEmpty checks there has no record waiting for draining, the expired chunks are removed when draining
+func (s *diskSpool) IsEmpty() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.chunks) == 0
+}
+
+// Close closes the writing chunk, the chunks are kept in the directory for the next process
+func (s *diskSpool) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Close()
+	s.writer = nil
+	return err
+}
+
+// oldestChunk returns the oldest unexpired chunk and the count of reported data in the removed expired chunks,
+// the writing would use a new chunk if it is the writing chunk
+func (s *diskSpool) oldestChunk() (*spoolChunk, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	expired := s.removeExpired()
+	if len(s.chunks) == 0 {
+		return nil, expired
+	}
+	if len(s.chunks) == 1 && s.writer != nil {
+		_ = s.writer.Close()
+		s.writer = nil
+	}
+	return s.chunks[0], expired
+}
+
+func (s *diskSpool) rotate() error {
+	if s.writer != nil {
+		if err := s.writer.Close(); err != nil {
+			return err
+		}
+		s.writer = nil
+	}
+	s.seq++
+	path := filepath.Join(s.dir, strconv.FormatUint(s.seq, 10)+spoolChunkSuffix)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	s.writer = file
+	s.chunks = append(s.chunks, &spoolChunk{path: path, modified: time.Now()})
+	return nil
+}
+
+// removeExpired removes the chunks not modified longer than the max age,
+// returns the count of reported data which have not been committed in them
+func (s *diskSpool) removeExpired() (count int) {
+	if s.maxAge <= 0 {
+		return 0
+	}
+	expired := time.Now().Add(-s.maxAge)
+	for len(s.chunks) > 0 && s.chunks[0].modified.Before(expired) {
+		count += s.removeOldest()
+	}
+	return count
+}
+
+// removeOldest removes the oldest chunk, returns the count of reported data which have not been committed in it
+func (s *diskSpool) removeOldest() int {
+	oldest := s.chunks[0]
+	if len(s.chunks) == 1 && s.writer != nil {
+		_ = s.writer.Close()
+		s.writer = nil
+	}
+	s.chunks = s.chunks[1:]
+	s.size -= oldest.size
+	_ = os.Remove(oldest.path)
+	_ = os.Remove(oldest.offsetPath())
+	return oldest.count
+}
+
+func parseSpoolChunkSeq(name string) (uint64, bool) {
+	if !strings.HasSuffix(name, spoolChunkSuffix) {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolChunkSuffix), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+// spoolTracing moves the segments waiting for sending into the spool until the wait duration reached,
+// returns false if the sending channel is closed
+func (r *gRPCReporter) spoolTracing(wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case s, ok := <-r.tracingSendCh:
+			if !ok {
+				return false
+			}
+			r.writeSpool(r.tracingSpool, reporter.DataTypeSegment, s, 1)
+			r.pending.Done()
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// spoolMetrics moves the metrics waiting for sending into the spool until the wait duration reached,
+// returns false if the sending channel is closed
+func (r *gRPCReporter) spoolMetrics(wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case s, ok := <-r.metricsSendCh:
+			if !ok {
+				return false
+			}
+			r.writeSpool(r.metricsSpool, reporter.DataTypeMeter, &agentv3.MeterDataCollection{MeterData: s}, len(s))
+			r.pending.Done()
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// spoolLogs moves the logs waiting for sending into the spool until the wait duration reached,
+// returns false if the sending channel is closed
+func (r *gRPCReporter) spoolLogs(wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case s, ok := <-r.logSendCh:
+			if !ok {
+				return false
+			}
+			r.writeSpool(r.logSpool, reporter.DataTypeLog, s, 1)
+			r.pending.Done()
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// drainTracingSpool sends the spooled segments in the streams opened for draining,
+// the segments are committed only after the stream closed successfully
+func (r *gRPCReporter) drainTracingSpool() error {
+	if r.tracingSpool == nil {
+		return nil
+	}
+	var stream agentv3.TraceSegmentReportService_CollectClient
+	expired, dropped, err := r.tracingSpool.Drain(func(data []byte) (err error) {
+		segment := &agentv3.SegmentObject{}
+		if err = proto.Unmarshal(data, segment); err != nil {
+			r.logger.Errorf("unmarshal spooled segment error %v", err)
+			return nil
+		}
+		if stream == nil {
+			if stream, err = r.traceClient.Collect(metadata.NewOutgoingContext(context.Background(), r.md)); err != nil {
+				return err
+			}
+		}
+		return stream.Send(segment)
+	}, func() (err error) {
+		if stream != nil {
+			_, err = stream.CloseAndRecv()
+			stream = nil
+		}
+		return ignoreStreamEOF(err)
+	})
+	if stream != nil {
+		r.closeTracingStream(stream)
+	}
+	r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonSpoolExpired, expired)
+	r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonSpoolFailure, dropped)
+	return err
+}
+
+// drainMetricsSpool sends the spooled metrics in the streams opened for draining,
+// the metrics are committed only after the stream closed successfully
+func (r *gRPCReporter) drainMetricsSpool() error {
+	if r.metricsSpool == nil {
+		return nil
+	}
+	var stream agentv3.MeterReportService_CollectBatchClient
+	expired, dropped, err := r.metricsSpool.Drain(func(data []byte) (err error) {
+		collection := &agentv3.MeterDataCollection{}
+		if err = proto.Unmarshal(data, collection); err != nil {
+			r.logger.Errorf("unmarshal spooled metrics error %v", err)
+			return nil
+		}
+		if stream == nil {
+			if stream, err = r.metricsClient.CollectBatch(metadata.NewOutgoingContext(context.Background(), r.md)); err != nil {
+				return err
+			}
+		}
+		return stream.Send(collection)
+	}, func() (err error) {
+		if stream != nil {
+			_, err = stream.CloseAndRecv()
+			stream = nil
+		}
+		return ignoreStreamEOF(err)
+	})
+	if stream != nil {
+		r.closeMetricsStream(stream)
+	}
+	r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonSpoolExpired, expired)
+	r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonSpoolFailure, dropped)
+	return err
+}
+
+// drainLogSpool sends the spooled logs in the streams opened for draining,
+// the logs are committed only after the stream closed successfully
+func (r *gRPCReporter) drainLogSpool() error {
+	if r.logSpool == nil {
+		return nil
+	}
+	var stream logv3.LogReportService_CollectClient
+	expired, dropped, err := r.logSpool.Drain(func(data []byte) (err error) {
+		log := &logv3.LogData{}
+		if err = proto.Unmarshal(data, log); err != nil {
+			r.logger.Errorf("unmarshal spooled log error %v", err)
+			return nil
+		}
+		if stream == nil {
+			if stream, err = r.logClient.Collect(metadata.NewOutgoingContext(context.Background(), r.md)); err != nil {
+				return err
+			}
+		}
+		return stream.Send(log)
+	}, func() (err error) {
+		if stream != nil {
+			_, err = stream.CloseAndRecv()
+			stream = nil
+		}
+		return ignoreStreamEOF(err)
+	})
+	if stream != nil {
+		r.closeLogStream(stream)
+	}
+	r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonSpoolExpired, expired)
+	r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonSpoolFailure, dropped)
+	return err
+}
+
+func ignoreStreamEOF(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// spoolFailedData writes the data failed to send into the spool, the data is dropped when the spool is disabled
+func (r *gRPCReporter) spoolFailedData(spool *diskSpool, dataType string, message proto.Message, count int) {
+	if spool == nil {
+		r.ObserveDropped(dataType, reporter.DropReasonSendFailure, count)
+		return
+	}
+	r.writeSpool(spool, dataType, message, count)
+}
+
+// writeSpool writes the message into the spool, the count is the count of data in the message for observing the dropped data
+func (r *gRPCReporter) writeSpool(spool *diskSpool, dataType string, message proto.Message, count int) {
+	if spool == nil {
+		return
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		r.logger.Errorf("marshal the spooling data error %v", err)
+		r.ObserveDropped(dataType, reporter.DropReasonSpoolFailure, count)
+		return
+	}
+	dropped, err := spool.Write(data, count)
+	if err != nil {
+		r.logger.Errorf("write the spool %s error %v", spool.dir, err)
+		r.ObserveDropped(dataType, reporter.DropReasonSpoolFailure, count)
+		return
+	}
+	if dropped > 0 {
+		r.logger.Warnf("reach max spool size, dropped %d oldest %s data of %s", dropped, dataType, spool.dir)
+		r.ObserveDropped(dataType, reporter.DropReasonSpoolFull, dropped)
+	}
+}
+
+func (r *gRPCReporter) closeSpool(spool *diskSpool) {
+	if spool == nil {
+		return
+	}
+	if err := spool.Close(); err != nil {
+		r.logger.Errorf("close the spool %s error %v", spool.dir, err)
+	}
+}
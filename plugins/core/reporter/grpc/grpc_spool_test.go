@@ -0,0 +1,268 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+func drainSpool(t *testing.T, spool *diskSpool) []string {
+	var result []string
+	_, _, err := spool.Drain(func(data []byte) error {
+		result = append(result, string(data))
+		return nil
+	}, func() error {
+		return nil
+	})
+	assert.Nil(t, err)
+	return result
+}
+
+func TestDiskSpoolDrainInOrder(t *testing.T) {
+	spool, err := newDiskSpool(t.TempDir(), 200, time.Hour)
+	assert.Nil(t, err)
+	var expected []string
+	for i := 0; i < 10; i++ {
+		data := fmt.Sprintf("data-%d", i)
+		expected = append(expected, data)
+		dropped, err := spool.Write([]byte(data), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, dropped)
+	}
+	assert.False(t, spool.IsEmpty())
+	assert.Equal(t, expected, drainSpool(t, spool))
+	assert.True(t, spool.IsEmpty())
+
+	_, err = spool.Write([]byte("after-drain"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"after-drain"}, drainSpool(t, spool))
+}
+
+func TestDiskSpoolMaxSize(t *testing.T) {
+	spool, err := newDiskSpool(t.TempDir(), 50, time.Hour)
+	assert.Nil(t, err)
+	dropped := 0
+	for i := 0; i < 20; i++ {
+		count, err := spool.Write([]byte(fmt.Sprintf("data-%02d", i)), 1)
+		assert.Nil(t, err)
+		dropped += count
+	}
+	assert.Greater(t, dropped, 0)
+	assert.LessOrEqual(t, spool.size, int64(50))
+	result := drainSpool(t, spool)
+	// every record is either dropped or drained
+	assert.Equal(t, 20, dropped+len(result))
+	assert.Equal(t, "data-19", result[len(result)-1])
+	assert.NotEqual(t, "data-00", result[0])
+}
+
+func TestDiskSpoolMaxAge(t *testing.T) {
+	spool, err := newDiskSpool(t.TempDir(), 1024, time.Millisecond*50)
+	assert.Nil(t, err)
+	_, err = spool.Write([]byte("expired"), 1)
+	assert.Nil(t, err)
+	time.Sleep(time.Millisecond * 100)
+	expired, dropped, err := spool.Drain(func(data []byte) error {
+		return fmt.Errorf("the expired data should not be sent")
+	}, func() error {
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, 0, dropped)
+	assert.True(t, spool.IsEmpty())
+}
+
+func TestDiskSpoolDrainFailure(t *testing.T) {
+	spool, err := newDiskSpool(t.TempDir(), 1024, time.Hour)
+	assert.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = spool.Write([]byte(fmt.Sprintf("data-%d", i)), 1)
+		assert.Nil(t, err)
+	}
+	var sent []string
+	_, _, err = spool.Drain(func(data []byte) error {
+		if len(sent) == 1 {
+			return fmt.Errorf("send failure")
+		}
+		sent = append(sent, string(data))
+		return nil
+	}, func() error {
+		return nil
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"data-0"}, sent)
+	// the sent record is not committed, so it should be sent again
+	assert.Equal(t, []string{"data-0", "data-1", "data-2"}, drainSpool(t, spool))
+}
+
+func TestDiskSpoolCommitFailure(t *testing.T) {
+	spool, err := newDiskSpool(t.TempDir(), 1024, time.Hour)
+	assert.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = spool.Write([]byte(fmt.Sprintf("data-%d", i)), 1)
+		assert.Nil(t, err)
+	}
+	var sent []string
+	_, _, err = spool.Drain(func(data []byte) error {
+		sent = append(sent, string(data))
+		return nil
+	}, func() error {
+		return fmt.Errorf("commit failure")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"data-0", "data-1", "data-2"}, sent)
+	assert.False(t, spool.IsEmpty())
+	// all the records should be sent again, as the stream is not closed successfully
+	assert.Equal(t, []string{"data-0", "data-1", "data-2"}, drainSpool(t, spool))
+	assert.True(t, spool.IsEmpty())
+}
+
+func TestDiskSpoolReload(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := newDiskSpool(dir, 1024, time.Hour)
+	assert.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = spool.Write([]byte(fmt.Sprintf("data-%d", i)), 1)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, spool.Close())
+	// the broken record at the end of chunk should be ignored
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "100"+spoolChunkSuffix), []byte{0, 0, 0, 9, 'x'}, 0o644))
+
+	reloaded, err := newDiskSpool(dir, 1024, time.Hour)
+	assert.Nil(t, err)
+	_, err = reloaded.Write([]byte("data-3"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"data-0", "data-1", "data-2", "data-3"}, drainSpool(t, reloaded))
+}
+
+func TestDiskSpoolReloadDrainedOffset(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := newDiskSpool(dir, 1024, time.Hour)
+	assert.Nil(t, err)
+	spool.commitRecords = 1
+	for i := 0; i < 3; i++ {
+		_, err = spool.Write([]byte(fmt.Sprintf("data-%d", i)), 1)
+		assert.Nil(t, err)
+	}
+	var sent []string
+	_, _, err = spool.Drain(func(data []byte) error {
+		if len(sent) == 1 {
+			return fmt.Errorf("send failure")
+		}
+		sent = append(sent, string(data))
+		return nil
+	}, func() error {
+		return nil
+	})
+	assert.NotNil(t, err)
+	assert.Nil(t, spool.Close())
+
+	// the committed records should not be sent again by the next process
+	reloaded, err := newDiskSpool(dir, 1024, time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"data-1", "data-2"}, drainSpool(t, reloaded))
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries, "the chunk and offset files should be removed after drained")
+}
+
+type testDropObserver struct {
+	dropped map[string]int
+}
+
+func (o *testDropObserver) Dropped(dataType, reason string, count int) {
+	o.dropped[dataType+"/"+reason] += count
+}
+func (o *testDropObserver) Reconnected(dataType string)                 {}
+func (o *testDropObserver) Sent(dataType string, latency time.Duration) {}
+
+func TestGRPCReporterSpoolDropped(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := newDiskSpool(dir, 50, time.Hour)
+	assert.Nil(t, err)
+	observer := &testDropObserver{dropped: make(map[string]int)}
+	r := &gRPCReporter{logger: operator.NewTestLogger(), tracingSpool: spool}
+	r.SetSelfObserver(observer)
+
+	for i := 0; i < 20; i++ {
+		r.writeSpool(spool, reporter.DataTypeSegment, &agentv3.SegmentObject{TraceSegmentId: fmt.Sprintf("%02d", i)}, 1)
+	}
+	evicted := observer.dropped[reporter.DataTypeSegment+"/"+reporter.DropReasonSpoolFull]
+	assert.Greater(t, evicted, 0)
+
+	// the oldest chunk cannot be read, all the records in it are dropped
+	oldest, records := spool.chunks[0].path, spool.chunks[0].count
+	assert.Nil(t, os.Remove(oldest))
+	assert.Nil(t, os.Mkdir(oldest, 0o755))
+	assert.NotNil(t, r.drainTracingSpool())
+	assert.Equal(t, records, observer.dropped[reporter.DataTypeSegment+"/"+reporter.DropReasonSpoolFailure])
+	assert.Equal(t, 20-evicted-records, len(drainSpool(t, spool)))
+}
+
+func TestDiskSpoolReloadRecords(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := newDiskSpool(dir, 1024, time.Hour)
+	assert.Nil(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = spool.Write([]byte(fmt.Sprintf("data-%d", i)), 1)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, spool.Close())
+
+	reloaded, err := newDiskSpool(dir, 1024, time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, reloaded.chunks[0].count)
+}
+
+func TestGRPCReporterSpoolMetersDropped(t *testing.T) {
+	spool, err := newDiskSpool(t.TempDir(), 200, time.Millisecond*50)
+	assert.Nil(t, err)
+	observer := &testDropObserver{dropped: make(map[string]int)}
+	r := &gRPCReporter{logger: operator.NewTestLogger(), metricsSpool: spool}
+	r.SetSelfObserver(observer)
+
+	// every collection contains 3 meters, the dropped count should be the count of meters
+	written := 0
+	for i := 0; i < 20; i++ {
+		meters := []*agentv3.MeterData{{Service: "service"}, {Service: "service"}, {Service: "service"}}
+		r.writeSpool(spool, reporter.DataTypeMeter, &agentv3.MeterDataCollection{MeterData: meters}, len(meters))
+		written += len(meters)
+	}
+	evicted := observer.dropped[reporter.DataTypeMeter+"/"+reporter.DropReasonSpoolFull]
+	assert.Greater(t, evicted, 0)
+	assert.Equal(t, 0, evicted%3)
+
+	// the left meters are expired before draining
+	time.Sleep(time.Millisecond * 100)
+	assert.Nil(t, r.drainMetricsSpool())
+	assert.Equal(t, written-evicted, observer.dropped[reporter.DataTypeMeter+"/"+reporter.DropReasonSpoolExpired])
+	assert.True(t, spool.IsEmpty())
+}
@@ -0,0 +1,76 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+func TestGRPCReporterFlushAndShutdown(t *testing.T) {
+	server := startTestSegmentServer(t)
+	defer server.server.Stop()
+	rep, err := NewGRPCReporter(operator.NewTestLogger(), server.addr, WithCheckInterval(time.Second), WithCDS(-1))
+	assert.Nil(t, err)
+	r := rep.(*gRPCReporter)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+
+	for i := 0; i < 10; i++ {
+		r.pending.Add(1)
+		r.tracingSendCh <- &agentv3.SegmentObject{TraceSegmentId: "segment"}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, r.Flush(ctx))
+	assert.Equal(t, 0, len(r.tracingSendCh), "the queued segments should be taken when flushed")
+	assert.Equal(t, 0, r.pending.Count(), "the queued segments should be sent when flushed")
+	// the reporter keeps working after flushed
+	r.pending.Add(1)
+	r.tracingSendCh <- &agentv3.SegmentObject{TraceSegmentId: "after-flush"}
+
+	assert.Nil(t, r.Shutdown(ctx))
+	assert.Equal(t, int32(11), atomic.LoadInt32(&server.received), "all the pending segments should be sent when shutdown returned")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&r.closed))
+	// shutdown and close again should not panic
+	assert.Nil(t, r.Shutdown(ctx))
+	r.Close()
+}
+
+func TestGRPCReporterShutdownTimeout(t *testing.T) {
+	// the backend is unreachable, and the segments cannot be sent before the context done
+	rep, err := NewGRPCReporter(operator.NewTestLogger(), "127.0.0.1:1", WithCheckInterval(time.Second), WithCDS(-1))
+	assert.Nil(t, err)
+	r := rep.(*gRPCReporter)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	r.pending.Add(1)
+	r.tracingSendCh <- &agentv3.SegmentObject{TraceSegmentId: "segment"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, r.Flush(ctx))
+	assert.Equal(t, context.DeadlineExceeded, r.Shutdown(ctx))
+}
@@ -0,0 +1,461 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+	managementv3 "skywalking.apache.org/repo/goapi/collect/management/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+const (
+	httpMaxSendQueueSize     int32 = 30000
+	httpMaxSendBatchSize           = 100
+	defaultHTTPCheckInterval       = 20 * time.Second
+	defaultHTTPTimeout             = 10 * time.Second
+	httpAuthKey                    = "Authentication"
+	// the connection is treated as disconnected after the consecutive sending failures,
+	// so an occasional failure would not stop tracing
+	httpDisconnectFailureThreshold int32 = 3
+	// httpReporterHeader marks the requests sent by the reporter, so the http plugin would not trace them
+	httpReporterHeader = "Sw-Agent-Reporter"
+
+	httpSegmentsPath         = "/v3/segments"
+	httpLogsPath             = "/v3/logs"
+	httpMetersPath           = "/v3/meters"
+	httpReportPropertiesPath = "/v3/management/reportProperties"
+	httpKeepAlivePath        = "/v3/management/keepAlive"
+)
+
+// NewHTTPReporter create a new reporter to send data to the REST receivers of oap server as JSON.
+// The server address should be like "http://127.0.0.1:12800", the scheme is decided by the TLS config when it is not defined.
+func NewHTTPReporter(logger operator.LogOperator, serverAddr string, opts ...HTTPReporterOption) (reporter.Reporter, error) {
+	r := &httpReporter{
+		logger:           logger,
+		tracingSendCh:    make(chan *agentv3.SegmentObject, httpMaxSendQueueSize),
+		metricsSendCh:    make(chan []*agentv3.MeterData, httpMaxSendQueueSize),
+		logSendCh:        make(chan *logv3.LogData, httpMaxSendQueueSize),
+		checkInterval:    defaultHTTPCheckInterval,
+		timeout:          defaultHTTPTimeout,
+		header:           make(http.Header),
+		connectionStatus: int32(reporter.ConnectionStatusConnected),
+		checkDone:        make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	if serverAddr == "" {
+		return nil, fmt.Errorf("the backend address of the HTTP reporter is empty")
+	}
+	if !strings.Contains(serverAddr, "://") {
+		if r.tlsConfig != nil {
+			serverAddr = "https://" + serverAddr
+		} else {
+			serverAddr = "http://" + serverAddr
+		}
+	}
+	r.serverAddr = strings.TrimSuffix(serverAddr, "/")
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if r.tlsConfig != nil {
+		transport.TLSClientConfig = r.tlsConfig
+	}
+	r.client = &http.Client{Transport: transport, Timeout: r.timeout}
+	return r, nil
+}
+
+type httpReporter struct {
+	reporter.SelfObserverHolder
+	entity        *reporter.Entity
+	logger        operator.LogOperator
+	serverAddr    string
+	client        *http.Client
+	tracingSendCh chan *agentv3.SegmentObject
+	metricsSendCh chan []*agentv3.MeterData
+	logSendCh     chan *logv3.LogData
+	checkInterval time.Duration
+	timeout       time.Duration
+
+	header    http.Header
+	tlsConfig *tls.Config
+
+	// sendWaitGroup is used to wait for the sending goroutines finished
+	sendWaitGroup sync.WaitGroup
+	closeOnce     sync.Once
+	// pending counts the data queued but not finished sending, for flushing
+	pending reporter.PendingCounter
+	// checkDone is closed when the reporter closed, to stop reporting the properties and keep alive
+	checkDone chan struct{}
+
+	// meterUnsupported is 1 when the backend doesn't provide the meter receiver over HTTP
+	meterUnsupported int32
+	// bootFlag is set if Boot be executed
+	bootFlag bool
+	// connectionStatus and failureCount are updated by the sending goroutines
+	connectionStatus int32
+	failureCount     int32
+}
+
+func (r *httpReporter) Boot(entity *reporter.Entity, cdsWatchers []reporter.AgentConfigChangeWatcher) {
+	r.entity = entity
+	r.initSendPipeline()
+	r.check()
+	r.bootFlag = true
+}
+
+func (r *httpReporter) ConnectionStatus() reporter.ConnectionStatus {
+	return reporter.ConnectionStatus(atomic.LoadInt32(&r.connectionStatus))
+}
+
+func (r *httpReporter) SendTracing(spans []reporter.ReportedSpan) {
+	segmentObject := reporter.BuildSegmentObject(r.entity, spans)
+	if segmentObject == nil {
+		return
+	}
+	r.pending.Add(1)
+	defer func() {
+		// recover the panic caused by close tracingSendCh
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonClosed, 1)
+			r.logger.Errorf("reporter segment err %v", err)
+		}
+	}()
+	select {
+	case r.tracingSendCh <- segmentObject:
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonQueueFull, 1)
+		r.logger.Errorf("reach max tracing send buffer")
+	}
+}
+
+func (r *httpReporter) SendMetrics(metrics []reporter.ReportedMeter) {
+	meters := reporter.BuildMeterDataList(r.entity, metrics)
+	if len(meters) == 0 || atomic.LoadInt32(&r.meterUnsupported) == 1 {
+		return
+	}
+	r.pending.Add(1)
+	defer func() {
+		// recover the panic caused by close metricsSendCh
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonClosed, len(meters))
+			r.logger.Errorf("reporter metrics err %v", err)
+		}
+	}()
+	select {
+	case r.metricsSendCh <- meters:
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonQueueFull, len(meters))
+		r.logger.Errorf("reach max metrics send buffer")
+	}
+}
+
+func (r *httpReporter) SendLog(log *logv3.LogData) {
+	r.pending.Add(1)
+	defer func() {
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonClosed, 1)
+			r.logger.Errorf("reporter log err %v", err)
+		}
+	}()
+	select {
+	case r.logSendCh <- log:
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonQueueFull, 1)
+	}
+}
+
+func (r *httpReporter) QueueDepth(dataType string) int {
+	switch dataType {
+	case reporter.DataTypeSegment:
+		return len(r.tracingSendCh)
+	case reporter.DataTypeMeter:
+		return len(r.metricsSendCh)
+	case reporter.DataTypeLog:
+		return len(r.logSendCh)
+	}
+	return 0
+}
+
+func (r *httpReporter) Close() {
+	if r.bootFlag {
+		r.closeSendChannels()
+		return
+	}
+	r.client.CloseIdleConnections()
+}
+
+func (r *httpReporter) Flush(ctx context.Context) error {
+	if !r.bootFlag {
+		return nil
+	}
+	return reporter.WaitPendingFinished(ctx, &r.pending)
+}
+
+func (r *httpReporter) Shutdown(ctx context.Context) error {
+	if !r.bootFlag {
+		r.client.CloseIdleConnections()
+		return nil
+	}
+	r.closeSendChannels()
+	return reporter.WaitSendingFinished(ctx, &r.sendWaitGroup)
+}
+
+func (r *httpReporter) closeSendChannels() {
+	r.closeOnce.Do(func() {
+		close(r.checkDone)
+		close(r.tracingSendCh)
+		close(r.metricsSendCh)
+		close(r.logSendCh)
+	})
+}
+
+func (r *httpReporter) initSendPipeline() {
+	r.sendWaitGroup.Add(3)
+	go func() {
+		// close the idle connections after all the data sent
+		r.sendWaitGroup.Wait()
+		r.client.CloseIdleConnections()
+	}()
+	go func() {
+		defer r.sendWaitGroup.Done()
+		for s := range r.tracingSendCh {
+			batch := []proto.Message{s}
+		BatchLoop:
+			for len(batch) < httpMaxSendBatchSize {
+				select {
+				case next, ok := <-r.tracingSendCh:
+					if !ok {
+						break BatchLoop
+					}
+					batch = append(batch, next)
+				default:
+					break BatchLoop
+				}
+			}
+			r.postBatch(httpSegmentsPath, reporter.DataTypeSegment, batch)
+			r.pending.Add(-len(batch))
+		}
+	}()
+	go func() {
+		defer r.sendWaitGroup.Done()
+		for meters := range r.metricsSendCh {
+			r.sendMeters(meters)
+			r.pending.Done()
+		}
+	}()
+	go func() {
+		defer r.sendWaitGroup.Done()
+		for l := range r.logSendCh {
+			batch := []proto.Message{l}
+		BatchLoop:
+			for len(batch) < httpMaxSendBatchSize {
+				select {
+				case next, ok := <-r.logSendCh:
+					if !ok {
+						break BatchLoop
+					}
+					batch = append(batch, next)
+				default:
+					break BatchLoop
+				}
+			}
+			r.postBatch(httpLogsPath, reporter.DataTypeLog, batch)
+			r.pending.Add(-len(batch))
+		}
+	}()
+}
+
+// sendMeters sends the meters in one request, stops sending the meters when the backend doesn't support receiving them
+func (r *httpReporter) sendMeters(meters []*agentv3.MeterData) {
+	batch := make([]proto.Message, len(meters))
+	for i := range meters {
+		batch[i] = meters[i]
+	}
+	start := time.Now()
+	status, err := r.post(httpMetersPath, batch)
+	if status == http.StatusNotFound {
+		r.logger.Warnf("the backend doesn't support receiving meters over HTTP, the meters would not be sent")
+		atomic.StoreInt32(&r.meterUnsupported, 1)
+		return
+	}
+	if err != nil {
+		r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonSendFailure, len(batch))
+		r.logger.Errorf("send metrics error %v", err)
+		return
+	}
+	r.ObserveSent(reporter.DataTypeMeter, start)
+}
+
+// postBatch sends the batch of messages, the messages are dropped when sending failure
+func (r *httpReporter) postBatch(path, dataType string, batch []proto.Message) {
+	start := time.Now()
+	if _, err := r.post(path, batch); err != nil {
+		r.ObserveDropped(dataType, reporter.DropReasonSendFailure, len(batch))
+		r.logger.Errorf("send %s error %v", dataType, err)
+		return
+	}
+	r.ObserveSent(dataType, start)
+}
+
+// post sends the messages as a JSON array, and update the connection status by the result
+func (r *httpReporter) post(path string, messages []proto.Message) (int, error) {
+	body, err := marshalJSONArray(messages)
+	if err != nil {
+		return 0, err
+	}
+	return r.postJSON(path, body)
+}
+
+func (r *httpReporter) postJSON(path string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, r.serverAddr+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(httpReporterHeader, "true")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		r.updateConnectionStatus(false)
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	r.updateConnectionStatus(true)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("unexpected response status of %s: %s", path, resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// updateConnectionStatus marks the connection as disconnected after the consecutive failures reached the threshold
+func (r *httpReporter) updateConnectionStatus(success bool) {
+	if success {
+		atomic.StoreInt32(&r.failureCount, 0)
+		atomic.StoreInt32(&r.connectionStatus, int32(reporter.ConnectionStatusConnected))
+		return
+	}
+	if atomic.AddInt32(&r.failureCount, 1) >= httpDisconnectFailureThreshold {
+		atomic.StoreInt32(&r.connectionStatus, int32(reporter.ConnectionStatusDisconnect))
+	}
+}
+
+func (r *httpReporter) reportInstanceProperties() error {
+	body, err := protojson.Marshal(&managementv3.InstanceProperties{
+		Service:         r.entity.ServiceName,
+		ServiceInstance: r.entity.ServiceInstanceName,
+		Properties:      r.entity.Props,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.postJSON(httpReportPropertiesPath, body)
+	return err
+}
+
+func (r *httpReporter) keepAlive() error {
+	body, err := protojson.Marshal(&managementv3.InstancePingPkg{
+		Service:         r.entity.ServiceName,
+		ServiceInstance: r.entity.ServiceInstanceName,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.postJSON(httpKeepAlivePath, body)
+	return err
+}
+
+func (r *httpReporter) check() {
+	if r.checkInterval < 0 {
+		return
+	}
+	go func() {
+		instancePropertiesSubmitted := false
+		for {
+			if !instancePropertiesSubmitted {
+				if err := r.reportInstanceProperties(); err != nil {
+					r.logger.Errorf("report serviceInstance properties error %v", err)
+					if !r.waitNextCheck() {
+						return
+					}
+					continue
+				}
+				instancePropertiesSubmitted = true
+			}
+
+			if err := r.keepAlive(); err != nil {
+				r.logger.Errorf("send keep alive signal error %v", err)
+			}
+			if !r.waitNextCheck() {
+				return
+			}
+		}
+	}()
+}
+
+// waitNextCheck waits for the next check interval, returns false when the reporter is closed.
+func (r *httpReporter) waitNextCheck() bool {
+	select {
+	case <-time.After(r.checkInterval):
+		return true
+	case <-r.checkDone:
+		return false
+	}
+}
+
+func marshalJSONArray(messages []proto.Message) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteByte('[')
+	for i, m := range messages {
+		data, err := protojson.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
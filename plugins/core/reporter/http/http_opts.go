@@ -0,0 +1,71 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"crypto/tls"
+	"time"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+// HTTPReporterOption allows for functional options to adjust behavior
+// of a HTTP reporter to be created by NewHTTPReporter
+type HTTPReporterOption func(r *httpReporter)
+
+// WithHTTPCheckInterval setup service and endpoint registry check interval
+func WithHTTPCheckInterval(interval time.Duration) HTTPReporterOption {
+	return func(r *httpReporter) {
+		r.checkInterval = interval
+	}
+}
+
+// WithHTTPMaxSendQueueSize setup send span queue buffer length
+func WithHTTPMaxSendQueueSize(maxSendQueueSize int) HTTPReporterOption {
+	return func(r *httpReporter) {
+		r.tracingSendCh = make(chan *agentv3.SegmentObject, maxSendQueueSize)
+		r.metricsSendCh = make(chan []*agentv3.MeterData, maxSendQueueSize)
+		r.logSendCh = make(chan *logv3.LogData, maxSendQueueSize)
+	}
+}
+
+// WithHTTPTLSConfig setup transport layer security
+func WithHTTPTLSConfig(config *tls.Config) HTTPReporterOption {
+	return func(r *httpReporter) {
+		r.tlsConfig = config
+	}
+}
+
+// WithHTTPAuthentication used Authentication header for HTTP
+func WithHTTPAuthentication(auth string) HTTPReporterOption {
+	return func(r *httpReporter) {
+		if auth != "" {
+			r.header.Set(httpAuthKey, auth)
+		}
+	}
+}
+
+// WithHTTPTimeout setup the timeout of each HTTP request
+func WithHTTPTimeout(timeout time.Duration) HTTPReporterOption {
+	return func(r *httpReporter) {
+		if timeout > 0 {
+			r.timeout = timeout
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+type testMeter struct{}
+
+func (m *testMeter) Name() string              { return "test" }
+func (m *testMeter) Labels() map[string]string { return nil }
+func (m *testMeter) Value() float64            { return 1 }
+
+type receivedRequest struct {
+	auth string
+	body string
+}
+
+func TestHTTPReporter(t *testing.T) {
+	var locker sync.Mutex
+	received := make(map[string][]receivedRequest)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		locker.Lock()
+		received[req.URL.Path] = append(received[req.URL.Path], receivedRequest{auth: req.Header.Get("Authentication"), body: string(body)})
+		locker.Unlock()
+		if req.URL.Path == httpMetersPath {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	getReceived := func(path string) []receivedRequest {
+		locker.Lock()
+		defer locker.Unlock()
+		return received[path]
+	}
+
+	r, err := NewHTTPReporter(operator.NewTestLogger(), server.URL, WithHTTPAuthentication("test-token"), WithHTTPCheckInterval(time.Hour))
+	assert.Nil(t, err)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+
+	r.SendLog(&logv3.LogData{Service: "service", Endpoint: "/test"})
+	r.SendMetrics([]reporter.ReportedMeter{&testMeter{}})
+
+	assert.Eventually(t, func() bool {
+		return len(getReceived(httpLogsPath)) == 1 && len(getReceived(httpMetersPath)) == 1 &&
+			len(getReceived(httpKeepAlivePath)) == 1
+	}, time.Second, 10*time.Millisecond)
+	logs := getReceived(httpLogsPath)[0]
+	assert.Equal(t, "test-token", logs.auth)
+	assert.Contains(t, logs.body, `"endpoint":"/test"`)
+	assert.True(t, logs.body[0] == '[')
+	assert.Contains(t, getReceived(httpReportPropertiesPath)[0].body, `"serviceInstance":"instance"`)
+	assert.Equal(t, reporter.ConnectionStatusConnected, r.ConnectionStatus())
+
+	// the meters should not be sent anymore when the backend doesn't support
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&r.(*httpReporter).meterUnsupported) == 1
+	}, time.Second, 10*time.Millisecond)
+	r.SendMetrics([]reporter.ReportedMeter{&testMeter{}})
+	r.Close()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, len(getReceived(httpMetersPath)))
+}
+
+func TestHTTPReporterDisconnected(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	addr := server.URL
+	server.Close()
+
+	r, err := NewHTTPReporter(operator.NewTestLogger(), addr, WithHTTPCheckInterval(-1))
+	assert.Nil(t, err)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	r.SendLog(&logv3.LogData{Service: "service"})
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&r.(*httpReporter).failureCount) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, reporter.ConnectionStatusConnected, r.ConnectionStatus(), "a single failure should not disconnect")
+	for i := int32(1); i < httpDisconnectFailureThreshold; i++ {
+		r.SendLog(&logv3.LogData{Service: "service"})
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&r.(*httpReporter).failureCount) == i+1
+		}, time.Second, 10*time.Millisecond)
+	}
+	assert.Equal(t, reporter.ConnectionStatusDisconnect, r.ConnectionStatus())
+	r.Close()
+}
+
+func TestHTTPReporterStopKeepAliveAfterShutdown(t *testing.T) {
+	var keepAliveCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == httpKeepAlivePath {
+			atomic.AddInt32(&keepAliveCount, 1)
+		}
+	}))
+	defer server.Close()
+
+	r, err := NewHTTPReporter(operator.NewTestLogger(), server.URL, WithHTTPCheckInterval(10*time.Millisecond))
+	assert.Nil(t, err)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&keepAliveCount) > 1
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, r.Shutdown(ctx))
+	// wait for the in-progress keep alive request finished
+	time.Sleep(50 * time.Millisecond)
+	count := atomic.LoadInt32(&keepAliveCount)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, count, atomic.LoadInt32(&keepAliveCount))
+}
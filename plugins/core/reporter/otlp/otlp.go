@@ -0,0 +1,357 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+const (
+	otlpMaxSendQueueSize     int32 = 30000
+	otlpMaxSendBatchSize           = 100
+	defaultOTLPCheckInterval       = 20 * time.Second
+	defaultOTLPTimeout             = 10 * time.Second
+)
+
+// NewOTLPReporter create a new reporter to export the tracing, metrics and log data to the OpenTelemetry collector.
+// The protocol could be OTLPProtocolGRPC(default) or OTLPProtocolHTTP.
+func NewOTLPReporter(logger operator.LogOperator, endpoint string, opts ...OTLPReporterOption) (reporter.Reporter, error) {
+	r := &otlpReporter{
+		logger:           logger,
+		protocol:         OTLPProtocolGRPC,
+		tracingSendCh:    make(chan []*tracepb.Span, otlpMaxSendQueueSize),
+		metricsSendCh:    make(chan []*metricspb.Metric, otlpMaxSendQueueSize),
+		logSendCh:        make(chan *logspb.LogRecord, otlpMaxSendQueueSize),
+		checkInterval:    defaultOTLPCheckInterval,
+		timeout:          defaultOTLPTimeout,
+		headers:          make(map[string]string),
+		connectionStatus: int32(reporter.ConnectionStatusConnected),
+		checkDone:        make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("the endpoint of the OTLP reporter is empty")
+	}
+	exporter, err := newOTLPExporter(r.protocol, endpoint, r.headers, r.tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	r.exporter = exporter
+	return r, nil
+}
+
+type otlpReporter struct {
+	reporter.SelfObserverHolder
+	entity        *reporter.Entity
+	resource      *resourcepb.Resource
+	logger        operator.LogOperator
+	exporter      otlpExporter
+	protocol      string
+	tracingSendCh chan []*tracepb.Span
+	metricsSendCh chan []*metricspb.Metric
+	logSendCh     chan *logspb.LogRecord
+	checkInterval time.Duration
+	timeout       time.Duration
+	bootTime      uint64
+
+	headers   map[string]string
+	tlsConfig *tls.Config
+
+	// sendWaitGroup is used to wait for the sending goroutines finished
+	sendWaitGroup sync.WaitGroup
+	closeOnce     sync.Once
+	// pending counts the data queued but not finished sending, for flushing
+	pending reporter.PendingCounter
+
+	// bootFlag is set if Boot be executed
+	bootFlag bool
+	// connectionStatus is updated by the check goroutine
+	connectionStatus int32
+	// checkDone is closed when the reporter closed, to stop checking the exporter
+	checkDone chan struct{}
+}
+
+func (r *otlpReporter) Boot(entity *reporter.Entity, cdsWatchers []reporter.AgentConfigChangeWatcher) {
+	r.entity = entity
+	r.resource = buildOTLPResource(entity)
+	r.bootTime = uint64(time.Now().UnixNano())
+	r.initSendPipeline()
+	r.check()
+	r.bootFlag = true
+}
+
+func (r *otlpReporter) ConnectionStatus() reporter.ConnectionStatus {
+	return reporter.ConnectionStatus(atomic.LoadInt32(&r.connectionStatus))
+}
+
+func (r *otlpReporter) SendTracing(spans []reporter.ReportedSpan) {
+	segmentObject := reporter.BuildSegmentObject(r.entity, spans)
+	if segmentObject == nil {
+		return
+	}
+	r.pending.Add(1)
+	defer func() {
+		// recover the panic caused by close tracingSendCh
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonClosed, 1)
+			r.logger.Errorf("reporter segment err %v", err)
+		}
+	}()
+	select {
+	case r.tracingSendCh <- buildOTLPSpans(segmentObject):
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonQueueFull, 1)
+		r.logger.Errorf("reach max tracing send buffer")
+	}
+}
+
+func (r *otlpReporter) SendMetrics(metrics []reporter.ReportedMeter) {
+	if len(metrics) == 0 {
+		return
+	}
+	r.pending.Add(1)
+	defer func() {
+		// recover the panic caused by close metricsSendCh
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonClosed, len(metrics))
+			r.logger.Errorf("reporter metrics err %v", err)
+		}
+	}()
+	select {
+	case r.metricsSendCh <- buildOTLPMetrics(metrics, r.bootTime, uint64(time.Now().UnixNano())):
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonQueueFull, len(metrics))
+		r.logger.Errorf("reach max metrics send buffer")
+	}
+}
+
+func (r *otlpReporter) SendLog(log *logv3.LogData) {
+	r.pending.Add(1)
+	defer func() {
+		if err := recover(); err != nil {
+			r.pending.Done()
+			r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonClosed, 1)
+			r.logger.Errorf("reporter log err %v", err)
+		}
+	}()
+	select {
+	case r.logSendCh <- buildOTLPLogRecord(log):
+	default:
+		r.pending.Done()
+		r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonQueueFull, 1)
+	}
+}
+
+func (r *otlpReporter) QueueDepth(dataType string) int {
+	switch dataType {
+	case reporter.DataTypeSegment:
+		return len(r.tracingSendCh)
+	case reporter.DataTypeMeter:
+		return len(r.metricsSendCh)
+	case reporter.DataTypeLog:
+		return len(r.logSendCh)
+	}
+	return 0
+}
+
+func (r *otlpReporter) Close() {
+	if r.bootFlag {
+		r.closeSendChannels()
+		return
+	}
+	r.exporter.close()
+}
+
+func (r *otlpReporter) Flush(ctx context.Context) error {
+	if !r.bootFlag {
+		return nil
+	}
+	return reporter.WaitPendingFinished(ctx, &r.pending)
+}
+
+func (r *otlpReporter) Shutdown(ctx context.Context) error {
+	if !r.bootFlag {
+		r.exporter.close()
+		return nil
+	}
+	r.closeSendChannels()
+	return reporter.WaitSendingFinished(ctx, &r.sendWaitGroup)
+}
+
+func (r *otlpReporter) closeSendChannels() {
+	r.closeOnce.Do(func() {
+		close(r.checkDone)
+		close(r.tracingSendCh)
+		close(r.metricsSendCh)
+		close(r.logSendCh)
+	})
+}
+
+func (r *otlpReporter) initSendPipeline() {
+	r.sendWaitGroup.Add(3)
+	go func() {
+		// close the exporter after all the data sent
+		r.sendWaitGroup.Wait()
+		r.exporter.close()
+	}()
+	go func() {
+		defer r.sendWaitGroup.Done()
+		for spans := range r.tracingSendCh {
+			batch := spans
+			segmentCount := 1
+		BatchLoop:
+			for len(batch) < otlpMaxSendBatchSize {
+				select {
+				case next, ok := <-r.tracingSendCh:
+					if !ok {
+						break BatchLoop
+					}
+					batch = append(batch, next...)
+					segmentCount++
+				default:
+					break BatchLoop
+				}
+			}
+			r.exportTraces(batch, segmentCount)
+			r.pending.Add(-segmentCount)
+		}
+	}()
+	go func() {
+		defer r.sendWaitGroup.Done()
+		for metrics := range r.metricsSendCh {
+			r.exportMetrics(metrics)
+			r.pending.Done()
+		}
+	}()
+	go func() {
+		defer r.sendWaitGroup.Done()
+		for l := range r.logSendCh {
+			batch := []*logspb.LogRecord{l}
+		BatchLoop:
+			for len(batch) < otlpMaxSendBatchSize {
+				select {
+				case next, ok := <-r.logSendCh:
+					if !ok {
+						break BatchLoop
+					}
+					batch = append(batch, next)
+				default:
+					break BatchLoop
+				}
+			}
+			r.exportLogs(batch)
+			r.pending.Add(-len(batch))
+		}
+	}()
+}
+
+func (r *otlpReporter) exportTraces(spans []*tracepb.Span, segmentCount int) {
+	ctx, cancel := otlpTimeoutContext(r.timeout)
+	defer cancel()
+	start := time.Now()
+	if err := r.exporter.exportTraces(ctx, &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource:   r.resource,
+			ScopeSpans: []*tracepb.ScopeSpans{{Scope: otlpScope(), Spans: spans}},
+		}},
+	}); err != nil {
+		r.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonSendFailure, segmentCount)
+		r.logger.Errorf("export spans error %v", err)
+		return
+	}
+	r.ObserveSent(reporter.DataTypeSegment, start)
+}
+
+func (r *otlpReporter) exportMetrics(metrics []*metricspb.Metric) {
+	ctx, cancel := otlpTimeoutContext(r.timeout)
+	defer cancel()
+	start := time.Now()
+	if err := r.exporter.exportMetrics(ctx, &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource:     r.resource,
+			ScopeMetrics: []*metricspb.ScopeMetrics{{Scope: otlpScope(), Metrics: metrics}},
+		}},
+	}); err != nil {
+		r.ObserveDropped(reporter.DataTypeMeter, reporter.DropReasonSendFailure, len(metrics))
+		r.logger.Errorf("export metrics error %v", err)
+		return
+	}
+	r.ObserveSent(reporter.DataTypeMeter, start)
+}
+
+func (r *otlpReporter) exportLogs(logs []*logspb.LogRecord) {
+	ctx, cancel := otlpTimeoutContext(r.timeout)
+	defer cancel()
+	start := time.Now()
+	if err := r.exporter.exportLogs(ctx, &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource:  r.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{Scope: otlpScope(), LogRecords: logs}},
+		}},
+	}); err != nil {
+		r.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonSendFailure, len(logs))
+		r.logger.Errorf("export logs error %v", err)
+		return
+	}
+	r.ObserveSent(reporter.DataTypeLog, start)
+}
+
+// check updates the connection status by the state of exporter periodically
+func (r *otlpReporter) check() {
+	if r.checkInterval < 0 {
+		return
+	}
+	go func() {
+		for {
+			if r.exporter.connected() {
+				atomic.StoreInt32(&r.connectionStatus, int32(reporter.ConnectionStatusConnected))
+			} else {
+				atomic.StoreInt32(&r.connectionStatus, int32(reporter.ConnectionStatusDisconnect))
+			}
+			select {
+			case <-time.After(r.checkInterval):
+			case <-r.checkDone:
+				return
+			}
+		}
+	}()
+}
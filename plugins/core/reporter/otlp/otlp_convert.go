@@ -0,0 +1,340 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+const (
+	otlpScopeName = "skywalking-go"
+
+	otlpAttrServiceName     = "service.name"
+	otlpAttrServiceInstance = "service.instance.id"
+	otlpAttrTraceID         = "skywalking.trace_id"
+	otlpAttrSegmentID       = "skywalking.segment_id"
+	otlpAttrSpanID          = "skywalking.span_id"
+	otlpAttrLayer           = "skywalking.layer"
+	otlpAttrComponent       = "skywalking.component_id"
+	otlpAttrPeer            = "skywalking.peer"
+	otlpAttrRefType         = "skywalking.ref_type"
+	otlpAttrParentService   = "skywalking.parent_service"
+	otlpAttrParentInstance  = "skywalking.parent_service_instance"
+	otlpAttrParentEndpoint  = "skywalking.parent_endpoint"
+	otlpAttrPeerAddress     = "skywalking.address_used_at_peer"
+	otlpAttrEndpoint        = "skywalking.endpoint"
+	otlpAttrLogLayer        = "skywalking.log_layer"
+	otlpLogLevelTag         = "LEVEL"
+	otlpSpanLogEventName    = "log"
+	otlpSpanLogEventKey     = "event"
+)
+
+// buildOTLPResource converts the service entity into the resource of OpenTelemetry,
+// the reported properties of the instance are kept as the resource attributes.
+func buildOTLPResource(entity *reporter.Entity) *resourcepb.Resource {
+	attrs := []*commonpb.KeyValue{
+		otlpStringAttr(otlpAttrServiceName, entity.ServiceName),
+		otlpStringAttr(otlpAttrServiceInstance, entity.ServiceInstanceName),
+	}
+	for _, p := range entity.Props {
+		attrs = append(attrs, otlpStringAttr(p.Key, p.Value))
+	}
+	return &resourcepb.Resource{Attributes: attrs}
+}
+
+// buildOTLPSpans converts the segment into the spans of OpenTelemetry.
+// The span inside the segment uses the previous span as the parent,
+// the first span of segment uses the first reference as the parent, and the others references are converted to the links.
+func buildOTLPSpans(segment *agentv3.SegmentObject) []*tracepb.Span {
+	traceID := otlpTraceID(segment.TraceId)
+	spans := make([]*tracepb.Span, 0, len(segment.Spans))
+	for _, s := range segment.Spans {
+		span := &tracepb.Span{
+			TraceId:           traceID,
+			SpanId:            otlpSpanID(segment.TraceSegmentId, s.SpanId),
+			Name:              s.OperationName,
+			Kind:              otlpSpanKind(s.SpanType, s.SpanLayer),
+			StartTimeUnixNano: otlpUnixNano(s.StartTime),
+			EndTimeUnixNano:   otlpUnixNano(s.EndTime),
+			Attributes: []*commonpb.KeyValue{
+				otlpStringAttr(otlpAttrTraceID, segment.TraceId),
+				otlpStringAttr(otlpAttrSegmentID, segment.TraceSegmentId),
+				otlpIntAttr(otlpAttrSpanID, int64(s.SpanId)),
+				otlpStringAttr(otlpAttrLayer, s.SpanLayer.String()),
+				otlpIntAttr(otlpAttrComponent, int64(s.ComponentId)),
+			},
+			Status: &tracepb.Status{},
+		}
+		if s.Peer != "" {
+			span.Attributes = append(span.Attributes, otlpStringAttr(otlpAttrPeer, s.Peer))
+		}
+		span.Attributes = append(span.Attributes, otlpKeyStringValueAttrs(s.Tags)...)
+		if s.IsError {
+			span.Status.Code = tracepb.Status_STATUS_CODE_ERROR
+		}
+		for _, l := range s.Logs {
+			span.Events = append(span.Events, buildOTLPSpanEvent(l))
+		}
+
+		refs := s.Refs
+		if s.SpanId != 0 && s.ParentSpanId >= 0 {
+			span.ParentSpanId = otlpSpanID(segment.TraceSegmentId, s.ParentSpanId)
+		} else if len(refs) > 0 {
+			span.ParentSpanId = otlpSpanID(refs[0].ParentTraceSegmentId, refs[0].ParentSpanId)
+			span.Attributes = append(span.Attributes, otlpRefAttrs(refs[0])...)
+			refs = refs[1:]
+		}
+		for _, ref := range refs {
+			span.Links = append(span.Links, &tracepb.Span_Link{
+				TraceId:    otlpTraceID(ref.TraceId),
+				SpanId:     otlpSpanID(ref.ParentTraceSegmentId, ref.ParentSpanId),
+				Attributes: otlpRefAttrs(ref),
+			})
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+func buildOTLPSpanEvent(l *agentv3.Log) *tracepb.Span_Event {
+	event := &tracepb.Span_Event{
+		TimeUnixNano: otlpUnixNano(l.Time),
+		Name:         otlpSpanLogEventName,
+	}
+	for _, kv := range l.Data {
+		if kv.Key == otlpSpanLogEventKey {
+			event.Name = kv.Value
+			continue
+		}
+		event.Attributes = append(event.Attributes, otlpStringAttr(kv.Key, kv.Value))
+	}
+	return event
+}
+
+// buildOTLPMetrics converts the meters, the single value meters are converted to the gauges,
+// and the histograms are converted to the cumulative histograms started from the startTime.
+func buildOTLPMetrics(meters []reporter.ReportedMeter, startTime, now uint64) []*metricspb.Metric {
+	result := make([]*metricspb.Metric, 0, len(meters))
+	for _, m := range meters {
+		attrs := make([]*commonpb.KeyValue, 0, len(m.Labels()))
+		for k, v := range m.Labels() {
+			attrs = append(attrs, otlpStringAttr(k, v))
+		}
+		switch data := m.(type) {
+		case reporter.ReportedMeterSingleValue:
+			result = append(result, &metricspb.Metric{
+				Name: data.Name(),
+				Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+					DataPoints: []*metricspb.NumberDataPoint{{
+						Attributes:        attrs,
+						StartTimeUnixNano: startTime,
+						TimeUnixNano:      now,
+						Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: data.Value()},
+					}},
+				}},
+			})
+		case reporter.ReportedMeterHistogram:
+			point := buildOTLPHistogramPoint(data.BucketValues())
+			point.Attributes = attrs
+			point.StartTimeUnixNano = startTime
+			point.TimeUnixNano = now
+			result = append(result, &metricspb.Metric{
+				Name: data.Name(),
+				Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+					DataPoints:             []*metricspb.HistogramDataPoint{point},
+					AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				}},
+			})
+		}
+	}
+	return result
+}
+
+// buildOTLPHistogramPoint converts the buckets, the bucket of SkyWalking is the lower bound of the values,
+// but the explicit bound of OpenTelemetry is the upper bound, so the values below the first bucket are counted as zero.
+func buildOTLPHistogramPoint(buckets []reporter.ReportedMeterBucketValue) *metricspb.HistogramDataPoint {
+	point := &metricspb.HistogramDataPoint{}
+	for i, b := range buckets {
+		if i == 0 && !b.IsNegativeInfinity() {
+			point.ExplicitBounds = append(point.ExplicitBounds, b.Bucket())
+			point.BucketCounts = append(point.BucketCounts, 0)
+		} else if i > 0 {
+			point.ExplicitBounds = append(point.ExplicitBounds, b.Bucket())
+		}
+		point.BucketCounts = append(point.BucketCounts, uint64(b.Count()))
+		point.Count += uint64(b.Count())
+	}
+	return point
+}
+
+// buildOTLPLogRecord converts the log data, the trace context is converted by the same rule of the spans
+func buildOTLPLogRecord(log *logv3.LogData) *logspb.LogRecord {
+	record := &logspb.LogRecord{
+		TimeUnixNano:         otlpUnixNano(log.Timestamp),
+		ObservedTimeUnixNano: otlpUnixNano(log.Timestamp),
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: otlpLogBody(log.Body)}},
+	}
+	if log.Endpoint != "" {
+		record.Attributes = append(record.Attributes, otlpStringAttr(otlpAttrEndpoint, log.Endpoint))
+	}
+	if log.Layer != "" {
+		record.Attributes = append(record.Attributes, otlpStringAttr(otlpAttrLogLayer, log.Layer))
+	}
+	if log.Tags != nil {
+		for _, kv := range log.Tags.Data {
+			if kv.Key == otlpLogLevelTag {
+				record.SeverityText = kv.Value
+				record.SeverityNumber = otlpSeverityNumber(kv.Value)
+				continue
+			}
+			record.Attributes = append(record.Attributes, otlpStringAttr(kv.Key, kv.Value))
+		}
+	}
+	if ctx := log.TraceContext; ctx != nil && ctx.TraceId != "" {
+		record.TraceId = otlpTraceID(ctx.TraceId)
+		record.SpanId = otlpSpanID(ctx.TraceSegmentId, ctx.SpanId)
+	}
+	return record
+}
+
+func otlpLogBody(body *logv3.LogDataBody) string {
+	switch {
+	case body == nil:
+		return ""
+	case body.GetText() != nil:
+		return body.GetText().Text
+	case body.GetJson() != nil:
+		return body.GetJson().Json
+	case body.GetYaml() != nil:
+		return body.GetYaml().Yaml
+	}
+	return ""
+}
+
+func otlpSeverityNumber(level string) logspb.SeverityNumber {
+	switch strings.ToLower(level) {
+	case "trace":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_TRACE
+	case "debug":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case "info":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case "warn", "warning":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case "error":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	case "fatal", "panic":
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	}
+	return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+}
+
+func otlpSpanKind(spanType agentv3.SpanType, layer agentv3.SpanLayer) tracepb.Span_SpanKind {
+	switch spanType {
+	case agentv3.SpanType_Entry:
+		if layer == agentv3.SpanLayer_MQ {
+			return tracepb.Span_SPAN_KIND_CONSUMER
+		}
+		return tracepb.Span_SPAN_KIND_SERVER
+	case agentv3.SpanType_Exit:
+		if layer == agentv3.SpanLayer_MQ {
+			return tracepb.Span_SPAN_KIND_PRODUCER
+		}
+		return tracepb.Span_SPAN_KIND_CLIENT
+	}
+	return tracepb.Span_SPAN_KIND_INTERNAL
+}
+
+func otlpRefAttrs(ref *agentv3.SegmentReference) []*commonpb.KeyValue {
+	attrs := []*commonpb.KeyValue{otlpStringAttr(otlpAttrRefType, ref.RefType.String())}
+	if ref.ParentService != "" {
+		attrs = append(attrs, otlpStringAttr(otlpAttrParentService, ref.ParentService))
+	}
+	if ref.ParentServiceInstance != "" {
+		attrs = append(attrs, otlpStringAttr(otlpAttrParentInstance, ref.ParentServiceInstance))
+	}
+	if ref.ParentEndpoint != "" {
+		attrs = append(attrs, otlpStringAttr(otlpAttrParentEndpoint, ref.ParentEndpoint))
+	}
+	if ref.NetworkAddressUsedAtPeer != "" {
+		attrs = append(attrs, otlpStringAttr(otlpAttrPeerAddress, ref.NetworkAddressUsedAtPeer))
+	}
+	return attrs
+}
+
+// otlpTraceID converts the trace ID of SkyWalking into 128-bit,
+// the hex format ID(such as UUID) is decoded directly, others are hashed.
+func otlpTraceID(traceID string) []byte {
+	if decoded, err := hex.DecodeString(strings.ReplaceAll(traceID, "-", "")); err == nil && len(decoded) == 16 {
+		return decoded
+	}
+	h := fnv.New128a()
+	_, _ = h.Write([]byte(traceID))
+	return h.Sum(nil)
+}
+
+// otlpSpanID converts the segment ID and the span ID inside the segment into 64-bit
+func otlpSpanID(segmentID string, spanID int32) []byte {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(segmentID))
+	_, _ = h.Write([]byte{'.'})
+	_, _ = h.Write([]byte(strconv.FormatInt(int64(spanID), 10)))
+	return h.Sum(nil)
+}
+
+func otlpUnixNano(millis int64) uint64 {
+	if millis <= 0 {
+		return 0
+	}
+	return uint64(millis) * 1e6
+}
+
+func otlpStringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func otlpIntAttr(key string, value int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value}}}
+}
+
+func otlpKeyStringValueAttrs(pairs []*commonv3.KeyStringValuePair) []*commonpb.KeyValue {
+	attrs := make([]*commonpb.KeyValue, 0, len(pairs))
+	for _, kv := range pairs {
+		attrs = append(attrs, otlpStringAttr(kv.Key, kv.Value))
+	}
+	return attrs
+}
+
+func otlpScope() *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{Name: otlpScopeName}
+}
@@ -0,0 +1,203 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+const (
+	// OTLPProtocolGRPC exports the data over OTLP/gRPC
+	OTLPProtocolGRPC = "grpc"
+	// OTLPProtocolHTTP exports the data over OTLP/HTTP with the binary protobuf encoding
+	OTLPProtocolHTTP = "http"
+
+	otlpTracesPath  = "/v1/traces"
+	otlpMetricsPath = "/v1/metrics"
+	otlpLogsPath    = "/v1/logs"
+	// otlpReporterHeader marks the requests sent by the reporter, so the http plugin would not trace them
+	otlpReporterHeader = "Sw-Agent-Reporter"
+)
+
+// otlpExporter sends the OTLP requests to the collector by the protocol
+type otlpExporter interface {
+	exportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error
+	exportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error
+	exportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error
+	connected() bool
+	close()
+}
+
+func newOTLPExporter(protocol, endpoint string, headers map[string]string, tlsConfig *tls.Config) (otlpExporter, error) {
+	switch protocol {
+	case OTLPProtocolGRPC, "":
+		return newOTLPGRPCExporter(endpoint, headers, tlsConfig)
+	case OTLPProtocolHTTP:
+		return newOTLPHTTPExporter(endpoint, headers, tlsConfig), nil
+	}
+	return nil, fmt.Errorf("unknown OTLP protocol: %s", protocol)
+}
+
+type otlpGRPCExporter struct {
+	conn    *grpc.ClientConn
+	md      metadata.MD
+	traces  coltracepb.TraceServiceClient
+	metrics colmetricspb.MetricsServiceClient
+	logs    collogspb.LogsServiceClient
+}
+
+func newOTLPGRPCExporter(endpoint string, headers map[string]string, tlsConfig *tls.Config) (*otlpGRPCExporter, error) {
+	var credsDialOption grpc.DialOption
+	if tlsConfig != nil {
+		credsDialOption = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	} else {
+		credsDialOption = grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+	conn, err := grpc.Dial(endpoint, credsDialOption)
+	if err != nil {
+		return nil, err
+	}
+	return &otlpGRPCExporter{
+		conn:    conn,
+		md:      metadata.New(headers),
+		traces:  coltracepb.NewTraceServiceClient(conn),
+		metrics: colmetricspb.NewMetricsServiceClient(conn),
+		logs:    collogspb.NewLogsServiceClient(conn),
+	}, nil
+}
+
+func (e *otlpGRPCExporter) exportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	_, err := e.traces.Export(metadata.NewOutgoingContext(ctx, e.md), req)
+	return err
+}
+
+func (e *otlpGRPCExporter) exportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	_, err := e.metrics.Export(metadata.NewOutgoingContext(ctx, e.md), req)
+	return err
+}
+
+func (e *otlpGRPCExporter) exportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	_, err := e.logs.Export(metadata.NewOutgoingContext(ctx, e.md), req)
+	return err
+}
+
+func (e *otlpGRPCExporter) connected() bool {
+	state := e.conn.GetState()
+	return state != connectivity.TransientFailure && state != connectivity.Shutdown
+}
+
+func (e *otlpGRPCExporter) close() {
+	_ = e.conn.Close()
+}
+
+type otlpHTTPExporter struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+}
+
+// newOTLPHTTPExporter create the exporter over HTTP, the endpoint should be like "http://127.0.0.1:4318",
+// the scheme is decided by the TLS config when it is not defined.
+func newOTLPHTTPExporter(endpoint string, headers map[string]string, tlsConfig *tls.Config) *otlpHTTPExporter {
+	if !strings.Contains(endpoint, "://") {
+		if tlsConfig != nil {
+			endpoint = "https://" + endpoint
+		} else {
+			endpoint = "http://" + endpoint
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return &otlpHTTPExporter{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		headers:  headers,
+		client:   &http.Client{Transport: transport},
+	}
+}
+
+func (e *otlpHTTPExporter) exportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	return e.post(ctx, otlpTracesPath, req)
+}
+
+func (e *otlpHTTPExporter) exportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	return e.post(ctx, otlpMetricsPath, req)
+}
+
+func (e *otlpHTTPExporter) exportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	return e.post(ctx, otlpLogsPath, req)
+}
+
+// connected always be true, the HTTP protocol is stateless, each failure of request would be logged
+func (e *otlpHTTPExporter) connected() bool {
+	return true
+}
+
+func (e *otlpHTTPExporter) close() {
+	e.client.CloseIdleConnections()
+}
+
+func (e *otlpHTTPExporter) post(ctx context.Context, path string, message proto.Message) error {
+	body, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set(otlpReporterHeader, "true")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the collector response status code %d for %s", resp.StatusCode, path)
+	}
+	return nil
+}
+
+func otlpTimeoutContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), timeout)
+}
@@ -0,0 +1,95 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"crypto/tls"
+	"strings"
+	"time"
+
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// OTLPReporterOption allows for functional options to adjust behavior
+// of a OTLP reporter to be created by NewOTLPReporter
+type OTLPReporterOption func(r *otlpReporter)
+
+// WithOTLPProtocol setup the protocol of exporting, it should be OTLPProtocolGRPC or OTLPProtocolHTTP
+func WithOTLPProtocol(protocol string) OTLPReporterOption {
+	return func(r *otlpReporter) {
+		if protocol != "" {
+			r.protocol = protocol
+		}
+	}
+}
+
+// WithOTLPCheckInterval setup the interval of checking the connection status
+func WithOTLPCheckInterval(interval time.Duration) OTLPReporterOption {
+	return func(r *otlpReporter) {
+		r.checkInterval = interval
+	}
+}
+
+// WithOTLPMaxSendQueueSize setup send span queue buffer length
+func WithOTLPMaxSendQueueSize(maxSendQueueSize int) OTLPReporterOption {
+	return func(r *otlpReporter) {
+		r.tracingSendCh = make(chan []*tracepb.Span, maxSendQueueSize)
+		r.metricsSendCh = make(chan []*metricspb.Metric, maxSendQueueSize)
+		r.logSendCh = make(chan *logspb.LogRecord, maxSendQueueSize)
+	}
+}
+
+// WithOTLPTLSConfig setup transport layer security
+func WithOTLPTLSConfig(config *tls.Config) OTLPReporterOption {
+	return func(r *otlpReporter) {
+		r.tlsConfig = config
+	}
+}
+
+// WithOTLPHeaders setup the headers(or gRPC metadata) of each export request
+func WithOTLPHeaders(headers map[string]string) OTLPReporterOption {
+	return func(r *otlpReporter) {
+		for k, v := range headers {
+			r.headers[k] = v
+		}
+	}
+}
+
+// WithOTLPTimeout setup the timeout of each export request
+func WithOTLPTimeout(timeout time.Duration) OTLPReporterOption {
+	return func(r *otlpReporter) {
+		if timeout > 0 {
+			r.timeout = timeout
+		}
+	}
+}
+
+// ParseOTLPHeaders parse the headers from the format "key1=value1,key2=value2"
+func ParseOTLPHeaders(headers string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(headers, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			continue
+		}
+		result[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return result
+}
@@ -0,0 +1,228 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+type testBucket struct {
+	bucket float64
+	count  int64
+	negInf bool
+}
+
+func (b *testBucket) Bucket() float64          { return b.bucket }
+func (b *testBucket) Count() int64             { return b.count }
+func (b *testBucket) IsNegativeInfinity() bool { return b.negInf }
+
+func TestBuildOTLPSpans(t *testing.T) {
+	segment := &agentv3.SegmentObject{
+		TraceId:        "0f3c4e0a-9a8b-4c1d-8e2f-3a4b5c6d7e8f",
+		TraceSegmentId: "segment-1",
+		Spans: []*agentv3.SpanObject{
+			{
+				SpanId: 1, ParentSpanId: 0, StartTime: 1000, EndTime: 1001, OperationName: "exit",
+				Peer: "localhost:6379", SpanType: agentv3.SpanType_Exit, SpanLayer: agentv3.SpanLayer_Cache,
+				ComponentId: 7, IsError: true,
+				Tags: []*commonv3.KeyStringValuePair{{Key: "cache.cmd", Value: "GET"}},
+				Logs: []*agentv3.Log{{Time: 1001, Data: []*commonv3.KeyStringValuePair{
+					{Key: "event", Value: "error"}, {Key: "message", Value: "timeout"},
+				}}},
+			},
+			{
+				SpanId: 0, ParentSpanId: -1, StartTime: 1000, EndTime: 1002, OperationName: "entry",
+				SpanType: agentv3.SpanType_Entry, SpanLayer: agentv3.SpanLayer_MQ,
+				Refs: []*agentv3.SegmentReference{
+					{RefType: agentv3.RefType_CrossProcess, TraceId: "0f3c4e0a-9a8b-4c1d-8e2f-3a4b5c6d7e8f", ParentTraceSegmentId: "parent-1",
+						ParentSpanId: 2, ParentService: "upstream"},
+					{RefType: agentv3.RefType_CrossProcess, TraceId: "other-trace", ParentTraceSegmentId: "parent-2", ParentSpanId: 3},
+				},
+			},
+		},
+	}
+	spans := buildOTLPSpans(segment)
+	assert.Equal(t, 2, len(spans))
+
+	traceID, _ := hex.DecodeString("0f3c4e0a9a8b4c1d8e2f3a4b5c6d7e8f")
+	exit, entry := spans[0], spans[1]
+	assert.Equal(t, traceID, exit.TraceId)
+	assert.Equal(t, otlpSpanID("segment-1", 1), exit.SpanId)
+	assert.Equal(t, entry.SpanId, exit.ParentSpanId)
+	assert.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, exit.Kind)
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, exit.Status.Code)
+	assert.Equal(t, uint64(1000*time.Millisecond), exit.StartTimeUnixNano)
+	assert.Equal(t, "Cache", findOTLPAttr(exit.Attributes, otlpAttrLayer).GetStringValue())
+	assert.Equal(t, int64(7), findOTLPAttr(exit.Attributes, otlpAttrComponent).GetIntValue())
+	assert.Equal(t, "localhost:6379", findOTLPAttr(exit.Attributes, otlpAttrPeer).GetStringValue())
+	assert.Equal(t, "GET", findOTLPAttr(exit.Attributes, "cache.cmd").GetStringValue())
+	assert.Equal(t, 1, len(exit.Events))
+	assert.Equal(t, "error", exit.Events[0].Name)
+	assert.Equal(t, "timeout", findOTLPAttr(exit.Events[0].Attributes, "message").GetStringValue())
+
+	assert.Equal(t, tracepb.Span_SPAN_KIND_CONSUMER, entry.Kind)
+	assert.Equal(t, otlpSpanID("parent-1", 2), entry.ParentSpanId)
+	assert.Equal(t, "upstream", findOTLPAttr(entry.Attributes, otlpAttrParentService).GetStringValue())
+	assert.Equal(t, 1, len(entry.Links))
+	assert.Equal(t, otlpTraceID("other-trace"), entry.Links[0].TraceId)
+	assert.Equal(t, 16, len(entry.Links[0].TraceId))
+	assert.Equal(t, otlpSpanID("parent-2", 3), entry.Links[0].SpanId)
+}
+
+func TestBuildOTLPHistogramPoint(t *testing.T) {
+	point := buildOTLPHistogramPoint([]reporter.ReportedMeterBucketValue{
+		&testBucket{bucket: 0, count: 1, negInf: true},
+		&testBucket{bucket: 10, count: 2},
+		&testBucket{bucket: 20, count: 3},
+	})
+	assert.Equal(t, []float64{10, 20}, point.ExplicitBounds)
+	assert.Equal(t, []uint64{1, 2, 3}, point.BucketCounts)
+	assert.Equal(t, uint64(6), point.Count)
+
+	point = buildOTLPHistogramPoint([]reporter.ReportedMeterBucketValue{
+		&testBucket{bucket: 5, count: 1},
+		&testBucket{bucket: 10, count: 2},
+	})
+	assert.Equal(t, []float64{5, 10}, point.ExplicitBounds)
+	assert.Equal(t, []uint64{0, 1, 2}, point.BucketCounts)
+}
+
+func TestBuildOTLPLogRecord(t *testing.T) {
+	record := buildOTLPLogRecord(&logv3.LogData{
+		Timestamp: 1000,
+		Endpoint:  "/test",
+		Body:      &logv3.LogDataBody{Content: &logv3.LogDataBody_Text{Text: &logv3.TextLog{Text: "hello"}}},
+		Tags:      &logv3.LogTags{Data: []*commonv3.KeyStringValuePair{{Key: "LEVEL", Value: "warn"}}},
+		TraceContext: &logv3.TraceContext{
+			TraceId: "trace", TraceSegmentId: "segment", SpanId: 1,
+		},
+	})
+	assert.Equal(t, "hello", record.Body.GetStringValue())
+	assert.Equal(t, "warn", record.SeverityText)
+	assert.Equal(t, otlpTraceID("trace"), record.TraceId)
+	assert.Equal(t, otlpSpanID("segment", 1), record.SpanId)
+	assert.Equal(t, "/test", findOTLPAttr(record.Attributes, otlpAttrEndpoint).GetStringValue())
+}
+
+func TestParseOTLPHeaders(t *testing.T) {
+	assert.Equal(t, map[string]string{"a": "1", "b": "x=y"}, ParseOTLPHeaders(" a = 1 ,b=x=y,invalid,"))
+	assert.Equal(t, map[string]string{}, ParseOTLPHeaders(""))
+}
+
+func TestOTLPReporterOverHTTP(t *testing.T) {
+	var locker sync.Mutex
+	var received []*coltracepb.ExportTraceServiceRequest
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != otlpTracesPath {
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		exportReq := &coltracepb.ExportTraceServiceRequest{}
+		if err := proto.Unmarshal(body, exportReq); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		locker.Lock()
+		received = append(received, exportReq)
+		headers = append(headers, req.Header)
+		locker.Unlock()
+	}))
+	defer server.Close()
+
+	r, err := NewOTLPReporter(operator.NewTestLogger(), server.URL, WithOTLPProtocol(OTLPProtocolHTTP),
+		WithOTLPHeaders(map[string]string{"api-key": "secret"}), WithOTLPCheckInterval(time.Hour))
+	assert.Nil(t, err)
+	r.Boot(&reporter.Entity{ServiceName: "service", ServiceInstanceName: "instance"}, nil)
+	r.SendTracing([]reporter.ReportedSpan{&testSpan{}})
+
+	assert.Eventually(t, func() bool {
+		locker.Lock()
+		defer locker.Unlock()
+		return len(received) == 1
+	}, time.Second*5, time.Millisecond*50)
+	r.Close()
+
+	locker.Lock()
+	defer locker.Unlock()
+	assert.Equal(t, "secret", headers[0].Get("api-key"))
+	assert.Equal(t, "application/x-protobuf", headers[0].Get("Content-Type"))
+	resourceSpans := received[0].ResourceSpans[0]
+	assert.Equal(t, "service", findOTLPAttr(resourceSpans.Resource.Attributes, otlpAttrServiceName).GetStringValue())
+	assert.Equal(t, "instance", findOTLPAttr(resourceSpans.Resource.Attributes, otlpAttrServiceInstance).GetStringValue())
+	assert.Equal(t, "test", resourceSpans.ScopeSpans[0].Spans[0].Name)
+}
+
+func TestNewOTLPReporterUnknownProtocol(t *testing.T) {
+	_, err := NewOTLPReporter(operator.NewTestLogger(), "127.0.0.1:4317", WithOTLPProtocol("unknown"))
+	assert.NotNil(t, err)
+}
+
+type testSpanContext struct{}
+
+func (c *testSpanContext) GetTraceID() string                           { return "trace" }
+func (c *testSpanContext) GetSegmentID() string                         { return "segment" }
+func (c *testSpanContext) GetSpanID() int32                             { return 0 }
+func (c *testSpanContext) GetParentSpanID() int32                       { return -1 }
+func (c *testSpanContext) GetParentSegmentID() string                   { return "" }
+func (c *testSpanContext) GetCorrelationContextValue(key string) string { return "" }
+func (c *testSpanContext) SetCorrelationContextValue(key, value string) {}
+
+type testSpan struct{}
+
+func (s *testSpan) Context() reporter.SegmentContext     { return &testSpanContext{} }
+func (s *testSpan) Refs() []reporter.SpanContext         { return nil }
+func (s *testSpan) StartTime() int64                     { return 1000 }
+func (s *testSpan) EndTime() int64                       { return 1001 }
+func (s *testSpan) OperationName() string                { return "test" }
+func (s *testSpan) Peer() string                         { return "" }
+func (s *testSpan) SpanType() agentv3.SpanType           { return agentv3.SpanType_Local }
+func (s *testSpan) SpanLayer() agentv3.SpanLayer         { return agentv3.SpanLayer_Unknown }
+func (s *testSpan) IsError() bool                        { return false }
+func (s *testSpan) Tags() []*commonv3.KeyStringValuePair { return nil }
+func (s *testSpan) Logs() []*agentv3.Log                 { return nil }
+func (s *testSpan) ComponentID() int32                   { return 0 }
+
+func findOTLPAttr(attrs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reporter
+
+import "time"
+
+// The data types of the reporting pipeline.
+const (
+	DataTypeSegment = "segment"
+	DataTypeMeter   = "meter"
+	DataTypeLog     = "log"
+)
+
+// The reasons of the data dropped by the reporter.
+const (
+	// DropReasonQueueFull means the sending queue is full
+	DropReasonQueueFull = "queue_full"
+	// DropReasonSendFailure means sending to the backend failure, and the data cannot be spooled
+	DropReasonSendFailure = "send_failure"
+	// DropReasonClosed means the reporter has been closed
+	DropReasonClosed = "closed"
+	// DropReasonSpoolFull means the oldest spooled data is evicted for keeping the max size of the spool
+	DropReasonSpoolFull = "spool_full"
+	// DropReasonSpoolExpired means the spooled data is not sent before reaching the max age of the spool
+	DropReasonSpoolExpired = "spool_expired"
+	// DropReasonSpoolFailure means writing or reading the spool failure
+	DropReasonSpoolFailure = "spool_failure"
+)
+
+// SelfObserver receives the self observability data of the reporting pipeline.
+type SelfObserver interface {
+	// Dropped is invoked when the count of data dropped by the reason
+	Dropped(dataType, reason string, count int)
+	// Reconnected is invoked when the stream of the data type reconnected to the backend
+	Reconnected(dataType string)
+	// Sent is invoked when the data has been sent, with the latency of sending
+	Sent(dataType string, latency time.Duration)
+}
+
+// SelfObservable is implemented by the reporter which exposes the self observability data.
+type SelfObservable interface {
+	// SetSelfObserver is invoked before the reporter booted
+	SetSelfObserver(observer SelfObserver)
+	// QueueDepth returns the count of data waiting for sending of the data type
+	QueueDepth(dataType string) int
+}
+
+// SelfObserverHolder holds the observer for the reporter, the data is ignored when the observer is not set.
+type SelfObserverHolder struct {
+	observer SelfObserver
+}
+
+func (h *SelfObserverHolder) SetSelfObserver(observer SelfObserver) {
+	h.observer = observer
+}
+
+func (h *SelfObserverHolder) ObserveDropped(dataType, reason string, count int) {
+	if h.observer != nil && count > 0 {
+		h.observer.Dropped(dataType, reason, count)
+	}
+}
+
+func (h *SelfObserverHolder) ObserveReconnected(dataType string) {
+	if h.observer != nil {
+		h.observer.Reconnected(dataType)
+	}
+}
+
+// ObserveSent records the latency from the start time of sending
+func (h *SelfObserverHolder) ObserveSent(dataType string, start time.Time) {
+	if h.observer != nil {
+		h.observer.Sent(dataType, time.Since(start))
+	}
+}
@@ -0,0 +1,80 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reporter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// flushCheckInterval is the interval of checking whether the pending data has been finished by the sending goroutines
+const flushCheckInterval = 10 * time.Millisecond
+
+// WaitSendingFinished waits all the sending goroutines in the wait group finished,
+// returns the error of context when the context done before that.
+func WaitSendingFinished(ctx context.Context, wg *sync.WaitGroup) error {
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PendingCounter counts the data which has been queued but not finished by the sending goroutines,
+// the data is finished after it has been sent, written, spooled or dropped.
+type PendingCounter struct {
+	count int64
+}
+
+// Add adds the delta to the count, the delta may be negative.
+func (c *PendingCounter) Add(delta int) {
+	atomic.AddInt64(&c.count, int64(delta))
+}
+
+// Done decrements the count by one.
+func (c *PendingCounter) Done() {
+	c.Add(-1)
+}
+
+// Count returns the count of the pending data.
+func (c *PendingCounter) Count() int {
+	return int(atomic.LoadInt64(&c.count))
+}
+
+// WaitPendingFinished waits until all the pending data has been finished by the sending goroutines without closing the queues,
+// returns the error of context when the context done before that.
+func WaitPendingFinished(ctx context.Context, pending *PendingCounter) error {
+	ticker := time.NewTicker(flushCheckInterval)
+	defer ticker.Stop()
+	for pending.Count() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reporter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// NewTLSConfig creates the TLS config for communicating with the backend,
+// the client key and cert chain are optional, they are only required when using mTLS.
+func NewTLSConfig(caPath, clientKeyPath, clientCertChainPath string, skipVerify bool) (*tls.Config, error) {
+	if err := checkTLSFile(caPath); err != nil {
+		return nil, err
+	}
+	tlsConfig := new(tls.Config)
+	tlsConfig.Renegotiation = tls.RenegotiateNever
+	tlsConfig.InsecureSkipVerify = skipVerify
+	caPem, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, err
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caPem) {
+		return nil, fmt.Errorf("failed to append certificates")
+	}
+	tlsConfig.RootCAs = certPool
+
+	if clientKeyPath != "" && clientCertChainPath != "" {
+		if err := checkTLSFile(clientKeyPath); err != nil {
+			return nil, err
+		}
+		if err := checkTLSFile(clientCertChainPath); err != nil {
+			return nil, err
+		}
+		clientPem, err := tls.LoadX509KeyPair(clientCertChainPath, clientKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientPem}
+	}
+	return tlsConfig, nil
+}
+
+// checkTLSFile checks the TLS files.
+func checkTLSFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.Size() == 0 {
+		return fmt.Errorf("the TLS file is illegal: %s", path)
+	}
+	return nil
+}
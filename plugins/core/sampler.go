@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"math/rand"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apache/skywalking-go/plugins/core/reporter"
@@ -103,15 +105,121 @@ func NewRandomSampler(samplingRate float64) *RandomSampler {
 	return s
 }
 
+// RateLimitSampler samples the limited count of traces in every time window.
+type RateLimitSampler struct {
+	limit  int32
+	window int64
+	// the start time(in nanoseconds) of current window, and the count of sampled traces in it
+	windowStart int64
+	count       int32
+}
+
+// IsSampled implements IsSampled() of Sampler.
+func (s *RateLimitSampler) IsSampled(_ string) bool {
+	now := time.Now().UnixNano()
+	start := atomic.LoadInt64(&s.windowStart)
+	if now-start >= s.window && atomic.CompareAndSwapInt64(&s.windowStart, start, now) {
+		atomic.StoreInt32(&s.count, 0)
+	}
+	if atomic.LoadInt32(&s.count) >= s.limit {
+		return false
+	}
+	return atomic.AddInt32(&s.count, 1) <= s.limit
+}
+
+// NewRateLimitSampler creates a RateLimitSampler, nothing is sampled when the limit is not greater than 0.
+func NewRateLimitSampler(limit int, window time.Duration) *RateLimitSampler {
+	return &RateLimitSampler{
+		limit:  int32(limit),
+		window: int64(window),
+	}
+}
+
+// samplingRule samples the operations which match the pattern by the rate
+type samplingRule struct {
+	pattern string
+	sampler Sampler
+}
+
+// RuleSampler samples the operation by the first matched rule, or by the default sampler when no rule matched.
+// The pattern of rule follows the Ant Path match style as the "trace_ignore_path".
+type RuleSampler struct {
+	rules          []*samplingRule
+	defaultSampler Sampler
+}
+
+// IsSampled implements IsSampled() of Sampler.
+func (s *RuleSampler) IsSampled(operation string) bool {
+	for _, rule := range s.rules {
+		if normalMatch(rule.pattern, 0, operation, 0) {
+			return rule.sampler.IsSampled(operation)
+		}
+	}
+	return s.defaultSampler.IsSampled(operation)
+}
+
+// NewRuleSampler creates a RuleSampler, the rules are formatted as "pattern:rate" and split by ",".
+func NewRuleSampler(rules string, defaultSampler Sampler) (*RuleSampler, error) {
+	s := &RuleSampler{defaultSampler: defaultSampler}
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		// the pattern could contain ":", such as "GET:/path", so the rate is split by the last ":"
+		inx := strings.LastIndex(rule, ":")
+		if inx <= 0 {
+			return nil, fmt.Errorf("invalid sampling rule: %s", rule)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rule[inx+1:]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling rate of rule: %s, %v", rule, err)
+		}
+		s.rules = append(s.rules, &samplingRule{pattern: strings.TrimSpace(rule[:inx]), sampler: newRateSampler(rate)})
+	}
+	return s, nil
+}
+
+// newRateSampler creates the sampler by the sampling rate
+func newRateSampler(samplingRate float64) Sampler {
+	if samplingRate <= 0 {
+		return NewConstSampler(false)
+	} else if samplingRate >= 1.0 {
+		return NewConstSampler(true)
+	}
+	return NewRandomSampler(samplingRate)
+}
+
+const (
+	// SamplerTypeRate samples the traces by the percentage
+	SamplerTypeRate = "rate"
+	// SamplerTypeRateLimit samples the limited count of traces in every time window
+	SamplerTypeRateLimit = "rate_limit"
+)
+
 type DynamicSampler struct {
-	currentRate float64
-	defaultRate float64
-	sampler     Sampler
+	// the lock serializes the changes of the sampler, which are triggered by the config watchers concurrently
+	lock             sync.Mutex
+	currentRate      float64
+	defaultRate      float64
+	currentRules     string
+	defaultRules     string
+	currentRateLimit int
+	defaultRateLimit int
+	// the window of rate limit sampler, sampling by the rate when it's 0
+	rateLimitWindow time.Duration
+	// the current sampler is published as *dynamicSamplerHolder, reading it without lock when sampling
+	sampler atomic.Value
+}
+
+// dynamicSamplerHolder wraps the sampler, as the atomic.Value requires the same type of the values
+type dynamicSamplerHolder struct {
+	sampler Sampler
 }
 
 // IsSampled implements IsSampled() of Sampler.
 func (s *DynamicSampler) IsSampled(operation string) bool {
-	return s.sampler.IsSampled(operation)
+	return s.sampler.Load().(*dynamicSamplerHolder).sampler.IsSampled(operation)
 }
 
 func (s *DynamicSampler) Key() string {
@@ -127,30 +235,117 @@ func (s *DynamicSampler) Notify(eventType reporter.AgentConfigEventType, newValu
 		return
 	}
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	// change Sampler
+	_ = s.changeSampler(samplingRate, s.currentRateLimit, s.currentRules)
+}
+
+func (s *DynamicSampler) Value() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return fmt.Sprintf("%f", s.currentRate)
+}
+
+// InitSamplerType switches to sample the limited count of traces in every window when the type is "rate_limit",
+// otherwise sampling by the rate.
+func (s *DynamicSampler) InitSamplerType(samplerType string, tracesPerWindow, windowSeconds int) {
+	if samplerType != SamplerTypeRateLimit || windowSeconds <= 0 {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.rateLimitWindow = time.Duration(windowSeconds) * time.Second
+	s.defaultRateLimit = tracesPerWindow
+	_ = s.changeSampler(s.currentRate, tracesPerWindow, s.currentRules)
+}
+
+// changeSampler rebuilds the sampler by the rate, rate limit and rules, keeps the current sampler when the rules are invalid.
+// The caller should hold the lock.
+func (s *DynamicSampler) changeSampler(samplingRate float64, rateLimit int, rules string) error {
 	var sampler Sampler
-	if samplingRate <= 0 {
-		sampler = NewConstSampler(false)
-	} else if samplingRate >= 1.0 {
-		sampler = NewConstSampler(true)
+	if s.rateLimitWindow > 0 {
+		sampler = NewRateLimitSampler(rateLimit, s.rateLimitWindow)
 	} else {
-		sampler = NewRandomSampler(samplingRate)
+		sampler = newRateSampler(samplingRate)
+	}
+	if strings.TrimSpace(rules) != "" {
+		ruleSampler, err := NewRuleSampler(rules, sampler)
+		if err != nil {
+			return err
+		}
+		sampler = ruleSampler
 	}
-	s.sampler = sampler
+	s.sampler.Store(&dynamicSamplerHolder{sampler: sampler})
 	s.currentRate = samplingRate
+	s.currentRateLimit = rateLimit
+	s.currentRules = rules
+	return nil
 }
 
-func (s *DynamicSampler) Value() string {
-	return fmt.Sprintf("%f", s.currentRate)
+// samplerRulesWatcher updates the sampling rules of DynamicSampler
+type samplerRulesWatcher struct {
+	sampler *DynamicSampler
+}
+
+func (w *samplerRulesWatcher) Key() string {
+	return "agent.sampler_rules"
+}
+
+func (w *samplerRulesWatcher) Notify(eventType reporter.AgentConfigEventType, newValue string) {
+	w.sampler.lock.Lock()
+	defer w.sampler.lock.Unlock()
+	if eventType == reporter.DELETED {
+		newValue = w.sampler.defaultRules
+	}
+	_ = w.sampler.changeSampler(w.sampler.currentRate, w.sampler.currentRateLimit, newValue)
 }
 
-func NewDynamicSampler(samplingRate float64, tracer *Tracer) *DynamicSampler {
+func (w *samplerRulesWatcher) Value() string {
+	w.sampler.lock.Lock()
+	defer w.sampler.lock.Unlock()
+	return w.sampler.currentRules
+}
+
+// samplerRateLimitWatcher updates the count of traces per window of DynamicSampler, works with the "rate_limit" sampler type
+type samplerRateLimitWatcher struct {
+	sampler *DynamicSampler
+}
+
+func (w *samplerRateLimitWatcher) Key() string {
+	return "agent.sampler_rate_limit.traces_per_window"
+}
+
+func (w *samplerRateLimitWatcher) Notify(eventType reporter.AgentConfigEventType, newValue string) {
+	w.sampler.lock.Lock()
+	defer w.sampler.lock.Unlock()
+	if eventType == reporter.DELETED {
+		newValue = strconv.Itoa(w.sampler.defaultRateLimit)
+	}
+	rateLimit, err := strconv.Atoi(newValue)
+	if err != nil {
+		return
+	}
+	_ = w.sampler.changeSampler(w.sampler.currentRate, rateLimit, w.sampler.currentRules)
+}
+
+func (w *samplerRateLimitWatcher) Value() string {
+	w.sampler.lock.Lock()
+	defer w.sampler.lock.Unlock()
+	return strconv.Itoa(w.sampler.currentRateLimit)
+}
+
+func NewDynamicSampler(samplingRate float64, rules string, tracer *Tracer) *DynamicSampler {
 	s := &DynamicSampler{
-		currentRate: samplingRate,
-		defaultRate: samplingRate,
+		currentRate:  samplingRate,
+		defaultRate:  samplingRate,
+		defaultRules: rules,
+	}
+	// ignore the invalid rules, then sampling by the rate
+	if err := s.changeSampler(samplingRate, 0, rules); err != nil {
+		s.Notify(reporter.MODIFY, fmt.Sprintf("%f", samplingRate))
 	}
-	s.Notify(reporter.MODIFY, fmt.Sprintf("%f", samplingRate))
 	// append watcher
-	tracer.cdsWatchers = append(tracer.cdsWatchers, s)
+	tracer.cdsWatchers = append(tracer.cdsWatchers, s, &samplerRulesWatcher{sampler: s}, &samplerRateLimitWatcher{sampler: s})
 	return s
 }
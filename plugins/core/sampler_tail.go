@@ -0,0 +1,97 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tailSampling keeps the segment when any span is error, or the duration is over the slow threshold,
+// or it's sampled by the base sampler. The decision is made when the root span of segment ended.
+type tailSampling struct {
+	slowThreshold      time.Duration
+	endpointThresholds []*endpointSlowThreshold
+}
+
+type endpointSlowThreshold struct {
+	pattern   string
+	threshold time.Duration
+}
+
+// InitTailSampling enables the tail-based sampling, the slow threshold is in milliseconds, not enabled when it's not greater than 0.
+// The endpoint thresholds are formatted as "pattern:threshold" and split by ",", the pattern follows the Ant Path match style.
+func (t *Tracer) InitTailSampling(enable bool, slowThreshold int, endpointThresholds string) {
+	if !enable {
+		return
+	}
+	ts := &tailSampling{slowThreshold: time.Duration(slowThreshold) * time.Millisecond}
+	for _, conf := range strings.Split(endpointThresholds, ",") {
+		conf = strings.TrimSpace(conf)
+		if conf == "" {
+			continue
+		}
+		// the endpoint could contain ":", such as "GET:/path", so the threshold is split by the last ":"
+		inx := strings.LastIndex(conf, ":")
+		if inx <= 0 {
+			t.Log.Warnf("ignore the invalid endpoint slow threshold of tail sampling: %s", conf)
+			continue
+		}
+		threshold, err := strconv.Atoi(strings.TrimSpace(conf[inx+1:]))
+		if err != nil {
+			t.Log.Warnf("ignore the invalid endpoint slow threshold of tail sampling: %s, %v", conf, err)
+			continue
+		}
+		ts.endpointThresholds = append(ts.endpointThresholds, &endpointSlowThreshold{
+			pattern:   strings.TrimSpace(conf[:inx]),
+			threshold: time.Duration(threshold) * time.Millisecond,
+		})
+	}
+	t.tailSampling = ts
+}
+
+// keep decides the finished segment should be reported or not
+func (ts *tailSampling) keep(root *RootSegmentSpan) bool {
+	if root.headSampled {
+		return true
+	}
+	if ts == nil {
+		return false
+	}
+	if root.IsError() {
+		return true
+	}
+	for _, span := range root.segment {
+		if span.IsError() {
+			return true
+		}
+	}
+	threshold := ts.thresholdOf(root.GetOperationName())
+	return threshold > 0 && root.DefaultSpan.EndTime.Sub(root.DefaultSpan.StartTime) >= threshold
+}
+
+// thresholdOf finds the slow threshold of the endpoint, the first matched pattern is used
+func (ts *tailSampling) thresholdOf(operationName string) time.Duration {
+	for _, e := range ts.endpointThresholds {
+		if normalMatch(e.pattern, 0, operationName, 0) {
+			return e.threshold
+		}
+	}
+	return ts.slowThreshold
+}
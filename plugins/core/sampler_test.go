@@ -20,6 +20,9 @@ package core
 import (
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
 )
 
 var samplerOperationName = "op"
@@ -91,6 +94,119 @@ func TestRandomSampler_getRandomizer(t *testing.T) {
 	})
 }
 
+func TestRuleSampler_IsSampled(t *testing.T) {
+	sampler, err := NewRuleSampler("GET:/health:0, POST:/checkout/**:1", NewConstSampler(false))
+	if err != nil {
+		t.Fatalf("rules should be valid: %v", err)
+	}
+	tests := map[string]bool{
+		"GET:/health":          false,
+		"POST:/checkout/order": true,
+		"GET:/checkout/order":  false,
+	}
+	for operation, expected := range tests {
+		if sampler.IsSampled(operation) != expected {
+			t.Errorf("the sampled result of %s should be %v", operation, expected)
+		}
+	}
+
+	if _, err := NewRuleSampler("GET:/health", NewConstSampler(true)); err == nil {
+		t.Errorf("rule without rate should be invalid")
+	}
+}
+
+func TestDynamicSampler_Rules(t *testing.T) {
+	tracer := &Tracer{}
+	sampler := NewDynamicSampler(1, "GET:/health:0", tracer)
+	if len(tracer.cdsWatchers) != 3 {
+		t.Fatalf("the sample rate, rules and rate limit watchers should be registered")
+	}
+	if sampler.IsSampled("GET:/health") || !sampler.IsSampled("GET:/users") {
+		t.Errorf("the health check should not be sampled only")
+	}
+	rulesWatcher := tracer.cdsWatchers[1]
+	rulesWatcher.Notify(reporter.MODIFY, "GET:/users:0")
+	if !sampler.IsSampled("GET:/health") || sampler.IsSampled("GET:/users") {
+		t.Errorf("the rules should be updated")
+	}
+	rulesWatcher.Notify(reporter.MODIFY, "invalid")
+	if rulesWatcher.Value() != "GET:/users:0" {
+		t.Errorf("the invalid rules should be ignored")
+	}
+	sampler.Notify(reporter.MODIFY, "0")
+	if sampler.IsSampled("GET:/health") {
+		t.Errorf("the rules should be kept when the rate changed")
+	}
+	rulesWatcher.Notify(reporter.DELETED, "")
+	if sampler.IsSampled("GET:/users") || rulesWatcher.Value() != "GET:/health:0" {
+		t.Errorf("the rules should be reset to default")
+	}
+}
+
+func TestRateLimitSampler_IsSampled(t *testing.T) {
+	sampler := NewRateLimitSampler(2, time.Millisecond*50)
+	for i := 0; i < 2; i++ {
+		if !sampler.IsSampled(samplerOperationName) {
+			t.Errorf("the traces in the limit should be sampled")
+		}
+	}
+	if sampler.IsSampled(samplerOperationName) {
+		t.Errorf("the traces over the limit should not be sampled")
+	}
+	time.Sleep(time.Millisecond * 60)
+	if !sampler.IsSampled(samplerOperationName) {
+		t.Errorf("the traces in the new window should be sampled")
+	}
+
+	if NewRateLimitSampler(0, time.Second).IsSampled(samplerOperationName) {
+		t.Errorf("nothing should be sampled when the limit is 0")
+	}
+}
+
+func TestDynamicSampler_RateLimit(t *testing.T) {
+	tracer := &Tracer{}
+	sampler := NewDynamicSampler(1, "", tracer)
+	sampler.InitSamplerType(SamplerTypeRateLimit, 1, 3)
+	if !sampler.IsSampled(samplerOperationName) || sampler.IsSampled(samplerOperationName) {
+		t.Errorf("only one trace should be sampled in the window")
+	}
+	rateLimitWatcher := tracer.cdsWatchers[2]
+	rateLimitWatcher.Notify(reporter.MODIFY, "2")
+	if !sampler.IsSampled(samplerOperationName) || !sampler.IsSampled(samplerOperationName) || sampler.IsSampled(samplerOperationName) {
+		t.Errorf("two traces should be sampled after the limit updated")
+	}
+	rateLimitWatcher.Notify(reporter.DELETED, "")
+	if rateLimitWatcher.Value() != "1" {
+		t.Errorf("the limit should be reset to default")
+	}
+}
+
+func TestDynamicSampler_ConcurrentChange(t *testing.T) {
+	tracer := &Tracer{}
+	sampler := NewDynamicSampler(1, "", tracer)
+	var wg sync.WaitGroup
+	for i, watcher := range tracer.cdsWatchers {
+		wg.Add(2)
+		go func(watcher reporter.AgentConfigChangeWatcher, value string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				watcher.Notify(reporter.MODIFY, value)
+				_ = watcher.Value()
+			}
+		}(watcher, []string{"0.5", "GET:/health:0", "10"}[i])
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sampler.IsSampled(samplerOperationName)
+			}
+		}()
+	}
+	wg.Wait()
+	if sampler.IsSampled("GET:/health") {
+		t.Errorf("the rules should be kept when the rate changed concurrently")
+	}
+}
+
 func BenchmarkRandomPoolSampler_IsSampled(b *testing.B) {
 	sampler := NewRandomSampler(0.5)
 	b.RunParallel(func(pb *testing.PB) {
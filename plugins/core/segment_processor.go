@@ -0,0 +1,390 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+
+	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const (
+	// the value of the tag redacted by the segment processor rules
+	redactedTagValue = "******"
+
+	processActionDropSpan    = "drop_span"
+	processActionDropSegment = "drop_segment"
+	processActionRename      = "rename"
+	processActionTag         = "tag"
+	processActionRemoveTag   = "remove_tag"
+	processActionRedact      = "redact"
+)
+
+// segmentProcessorChain processes the spans of the finished segment before reporting,
+// the configured rules are applied at first, then the processors registered by the plugins in order.
+type segmentProcessorChain struct {
+	rules      atomic.Value // []*segmentProcessRule
+	processors atomic.Value // []func(spans []interface{}) bool
+	lock       sync.Mutex
+}
+
+// InitSegmentProcessors configures the rules of the segment processors, the invalid rules are ignored,
+// and registers the watcher for updating the rules through the dynamic configuration.
+func (t *Tracer) InitSegmentProcessors(rules string) {
+	if parsed, err := parseSegmentProcessRules(rules); err != nil {
+		t.Log.Warnf("ignore the invalid segment processor rules: %v", err)
+	} else {
+		t.segmentProcessors.rules.Store(parsed)
+	}
+	t.cdsWatchers = append(t.cdsWatchers, newConfigWatcher("agent.segment_processors", rules, func(value string) error {
+		parsed, err := parseSegmentProcessRules(value)
+		if err != nil {
+			return err
+		}
+		t.segmentProcessors.rules.Store(parsed)
+		return nil
+	}))
+}
+
+// AddSegmentProcessor appends the processor to the end of the chain, the processor receives the spans which are not dropped,
+// every span could be changed or dropped, and the whole segment is dropped when the processor returns false.
+func (t *Tracer) AddSegmentProcessor(processor func(spans []interface{}) bool) {
+	if processor == nil {
+		return
+	}
+	t.segmentProcessors.lock.Lock()
+	defer t.segmentProcessors.lock.Unlock()
+	existing, _ := t.segmentProcessors.processors.Load().([]func(spans []interface{}) bool)
+	processors := make([]func(spans []interface{}) bool, 0, len(existing)+1)
+	processors = append(processors, existing...)
+	t.segmentProcessors.processors.Store(append(processors, processor))
+}
+
+// process returns the spans for reporting, the segment is dropped when it returns nil
+func (c *segmentProcessorChain) process(spans []reporter.ReportedSpan) []reporter.ReportedSpan {
+	rules, _ := c.rules.Load().([]*segmentProcessRule)
+	processors, _ := c.processors.Load().([]func(spans []interface{}) bool)
+	if len(rules) == 0 && len(processors) == 0 {
+		return spans
+	}
+	processedSpans := make([]*processedSpan, 0, len(spans))
+	for _, s := range spans {
+		p := newProcessedSpan(s)
+		if p == nil {
+			// only the spans of segment could be processed
+			return spans
+		}
+		processedSpans = append(processedSpans, p)
+	}
+
+	for _, rule := range rules {
+		for _, p := range processedSpans {
+			if p.dropped || !rule.match(p) {
+				continue
+			}
+			if !rule.apply(p) {
+				return nil
+			}
+		}
+	}
+	for _, processor := range processors {
+		notDropped := make([]interface{}, 0, len(processedSpans))
+		for _, p := range processedSpans {
+			if !p.dropped {
+				notDropped = append(notDropped, p)
+			}
+		}
+		if !processor(notDropped) {
+			return nil
+		}
+	}
+	return reparentDroppedSpans(processedSpans)
+}
+
+// reparentDroppedSpans removes the dropped spans, and links their children to their parents,
+// the whole segment is dropped when the first span is dropped
+func reparentDroppedSpans(spans []*processedSpan) []reporter.ReportedSpan {
+	for _, dropped := range spans {
+		if !dropped.dropped {
+			continue
+		}
+		if dropped.context.ParentSpanID < 0 {
+			return nil
+		}
+		for _, s := range spans {
+			if s.context.ParentSpanID == dropped.context.SpanID {
+				s.context.ParentSpanID = dropped.context.ParentSpanID
+			}
+		}
+	}
+	result := make([]reporter.ReportedSpan, 0, len(spans))
+	for _, s := range spans {
+		if !s.dropped {
+			result = append(result, s.reported)
+		}
+	}
+	return result
+}
+
+// processedSpan is the finished span which could be changed by the segment processors
+type processedSpan struct {
+	reported reporter.ReportedSpan
+	span     *DefaultSpan
+	context  *SegmentContext
+	dropped  bool
+}
+
+func newProcessedSpan(s reporter.ReportedSpan) *processedSpan {
+	switch span := s.(type) {
+	case *SegmentSpanImpl:
+		return &processedSpan{reported: s, span: &span.DefaultSpan, context: &span.SegmentContext}
+	case *RootSegmentSpan:
+		return &processedSpan{reported: s, span: &span.DefaultSpan, context: &span.SegmentContext}
+	}
+	return nil
+}
+
+func (p *processedSpan) OperationName() string {
+	return p.span.OperationName
+}
+
+func (p *processedSpan) SetOperationName(name string) {
+	p.span.OperationName = name
+}
+
+func (p *processedSpan) Peer() string {
+	return p.span.Peer
+}
+
+func (p *processedSpan) SpanLayer() int32 {
+	return int32(p.span.Layer)
+}
+
+func (p *processedSpan) ComponentID() int32 {
+	return p.span.ComponentID
+}
+
+func (p *processedSpan) IsEntry() bool {
+	return p.span.SpanType == SpanTypeEntry
+}
+
+func (p *processedSpan) IsExit() bool {
+	return p.span.SpanType == SpanTypeExit
+}
+
+func (p *processedSpan) IsError() bool {
+	return p.span.IsError
+}
+
+func (p *processedSpan) GetTag(key string) (string, bool) {
+	for _, tag := range p.span.Tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+func (p *processedSpan) SetTag(key, value string) {
+	p.span.Tag(key, value)
+}
+
+func (p *processedSpan) RemoveTag(key string) {
+	tags := p.span.Tags[:0]
+	for _, tag := range p.span.Tags {
+		if tag.Key != key {
+			tags = append(tags, tag)
+		}
+	}
+	p.span.Tags = tags
+}
+
+// Drop marks the span as dropped, the exit span, the span linked to other segments and the span captured
+// by other goroutines are kept, as the downstream, upstream or cross thread segments reference them
+func (p *processedSpan) Drop() {
+	if p.IsExit() || p.span.captured || (p.context.ParentSpanID >= 0 && len(p.span.Refs) > 0) {
+		return
+	}
+	p.dropped = true
+}
+
+type segmentProcessCondition func(p *processedSpan) bool
+
+// segmentProcessRule applies the action to the span when all the conditions are matched
+type segmentProcessRule struct {
+	conditions []segmentProcessCondition
+	action     string
+	key        string
+	value      string
+}
+
+func (r *segmentProcessRule) match(p *processedSpan) bool {
+	for _, condition := range r.conditions {
+		if !condition(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply the action to the span, returns false when the whole segment should be dropped
+func (r *segmentProcessRule) apply(p *processedSpan) bool {
+	switch r.action {
+	case processActionDropSpan:
+		p.Drop()
+	case processActionDropSegment:
+		return false
+	case processActionRename:
+		p.SetOperationName(r.value)
+	case processActionTag:
+		p.SetTag(r.key, r.value)
+	case processActionRemoveTag:
+		p.RemoveTag(r.key)
+	case processActionRedact:
+		if _, exist := p.GetTag(r.key); exist {
+			p.SetTag(r.key, redactedTagValue)
+		}
+	}
+	return true
+}
+
+// parseSegmentProcessRules parses the rules split by ";", every rule is formatted as "conditions=>action".
+// The conditions are split by "&", supports "layer=Database", "component=5", "type=entry",
+// "operation=GET:/api/**"(Ant Path match style) and "tag=key" or "tag=key:value".
+// The actions are "drop_span", "drop_segment", "rename=name", "tag=key:value", "remove_tag=key" and "redact=key".
+func parseSegmentProcessRules(rules string) ([]*segmentProcessRule, error) {
+	result := make([]*segmentProcessRule, 0)
+	for _, conf := range strings.Split(rules, ";") {
+		conf = strings.TrimSpace(conf)
+		if conf == "" {
+			continue
+		}
+		inx := strings.Index(conf, "=>")
+		if inx < 0 {
+			return nil, fmt.Errorf("the action is not found in the rule: %s", conf)
+		}
+		rule, err := parseSegmentProcessAction(strings.TrimSpace(conf[inx+2:]))
+		if err != nil {
+			return nil, fmt.Errorf("%v in the rule: %s", err, conf)
+		}
+		for _, c := range strings.Split(conf[:inx], "&") {
+			c = strings.TrimSpace(c)
+			if c == "" {
+				continue
+			}
+			condition, err := parseSegmentProcessCondition(c)
+			if err != nil {
+				return nil, fmt.Errorf("%v in the rule: %s", err, conf)
+			}
+			rule.conditions = append(rule.conditions, condition)
+		}
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+func parseSegmentProcessAction(action string) (*segmentProcessRule, error) {
+	name, value := action, ""
+	if inx := strings.Index(action, "="); inx >= 0 {
+		name, value = strings.TrimSpace(action[:inx]), strings.TrimSpace(action[inx+1:])
+	}
+	rule := &segmentProcessRule{action: name}
+	switch name {
+	case processActionDropSpan, processActionDropSegment:
+		return rule, nil
+	case processActionRename:
+		rule.value = value
+	case processActionRemoveTag, processActionRedact:
+		rule.key = value
+	case processActionTag:
+		inx := strings.Index(value, ":")
+		if inx <= 0 {
+			return nil, fmt.Errorf("the tag action must be formatted as \"tag=key:value\"")
+		}
+		rule.key, rule.value = value[:inx], value[inx+1:]
+	default:
+		return nil, fmt.Errorf("unknown action %s", name)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("the value of action %s is empty", name)
+	}
+	return rule, nil
+}
+
+func parseSegmentProcessCondition(condition string) (segmentProcessCondition, error) {
+	inx := strings.Index(condition, "=")
+	if inx <= 0 {
+		return nil, fmt.Errorf("the condition must be formatted as \"key=value\": %s", condition)
+	}
+	key, value := strings.TrimSpace(condition[:inx]), strings.TrimSpace(condition[inx+1:])
+	switch key {
+	case "layer":
+		layer, ok := parseSpanLayer(value)
+		if !ok {
+			return nil, fmt.Errorf("unknown span layer %s", value)
+		}
+		return func(p *processedSpan) bool {
+			return p.span.Layer == layer
+		}, nil
+	case "component":
+		component, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid component %s", value)
+		}
+		return func(p *processedSpan) bool {
+			return p.span.ComponentID == int32(component)
+		}, nil
+	case "type":
+		spanType, ok := map[string]SpanType{"entry": SpanTypeEntry, "exit": SpanTypeExit, "local": SpanTypeLocal}[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("unknown span type %s", value)
+		}
+		return func(p *processedSpan) bool {
+			return p.span.SpanType == spanType
+		}, nil
+	case "operation":
+		return func(p *processedSpan) bool {
+			return normalMatch(value, 0, p.span.OperationName, 0)
+		}, nil
+	case "tag":
+		tagKey, tagValue, withValue := value, "", false
+		if inx := strings.Index(value, ":"); inx > 0 {
+			tagKey, tagValue, withValue = value[:inx], value[inx+1:], true
+		}
+		return func(p *processedSpan) bool {
+			v, exist := p.GetTag(tagKey)
+			return exist && (!withValue || v == tagValue)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown condition %s", key)
+}
+
+func parseSpanLayer(name string) (agentv3.SpanLayer, bool) {
+	for value, layerName := range agentv3.SpanLayer_name {
+		if strings.EqualFold(layerName, name) {
+			return agentv3.SpanLayer(value), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,156 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+)
+
+const (
+	createdTracingContextMeterName  = "sw_go_created_tracing_context_counter"
+	finishedTracingContextMeterName = "sw_go_finished_tracing_context_counter"
+	possibleLeakedContextMeterName  = "sw_go_possible_leaked_context_counter"
+	reporterQueueDepthMeterName     = "sw_go_reporter_queue_depth"
+	reporterDroppedMeterName        = "sw_go_reporter_dropped_counter"
+	reporterReconnectMeterName      = "sw_go_reporter_reconnect_counter"
+	reporterSendLatencyMeterName    = "sw_go_reporter_send_latency"
+
+	// the tracing context is possibly leaked when it's not finished in the duration
+	possibleLeakedContextThreshold = 10 * time.Minute
+)
+
+var (
+	selfObservabilityDataTypes = []string{reporter.DataTypeSegment, reporter.DataTypeMeter, reporter.DataTypeLog}
+	// the buckets of sending latency, in milliseconds
+	reporterSendLatencySteps = []float64{1, 5, 10, 50, 100, 500, 1000, 5000}
+)
+
+// selfObserver records the meters of the agent itself, includes the tracing contexts and the reporting pipeline.
+type selfObserver struct {
+	tracer *Tracer
+
+	createdContexts  *counterImpl
+	finishedContexts *counterImpl
+	leakedContexts   *counterImpl
+	// the creation time of the tracing contexts which are not finished
+	activeContexts *sync.Map
+
+	droppedCounters   *sync.Map
+	reconnectCounters map[string]*counterImpl
+	sendLatencies     map[string]*histogramImpl
+}
+
+func newSelfObserver(t *Tracer) *selfObserver {
+	o := &selfObserver{
+		tracer:            t,
+		createdContexts:   newCounter(createdTracingContextMeterName, nil, 0),
+		finishedContexts:  newCounter(finishedTracingContextMeterName, nil, 0),
+		leakedContexts:    newCounter(possibleLeakedContextMeterName, nil, 0),
+		activeContexts:    &sync.Map{},
+		droppedCounters:   &sync.Map{},
+		reconnectCounters: make(map[string]*counterImpl),
+		sendLatencies:     make(map[string]*histogramImpl),
+	}
+	t.registerMetrics(o.createdContexts.name, nil, o.createdContexts)
+	t.registerMetrics(o.finishedContexts.name, nil, o.finishedContexts)
+	t.registerMetrics(o.leakedContexts.name, nil, o.leakedContexts)
+	for _, dataType := range selfObservabilityDataTypes {
+		labels := map[string]string{"type": dataType}
+		reconnect := newCounter(reporterReconnectMeterName, labels, 0)
+		t.registerMetrics(reconnect.name, labels, reconnect)
+		o.reconnectCounters[dataType] = reconnect
+		latency := newHistogramFromSteps(reporterSendLatencyMeterName, labels, 0, append([]float64{}, reporterSendLatencySteps...))
+		t.registerMetrics(latency.name, labels, latency)
+		o.sendLatencies[dataType] = latency
+	}
+	return o
+}
+
+// initSelfObservability registers the self observability meters, and binds the observer to the reporter if supported
+func (t *Tracer) initSelfObservability() {
+	observer := newSelfObserver(t)
+	t.selfObserver = observer
+	t.AddCollectHook(observer.checkLeakedContexts)
+	observable, ok := t.Reporter.(reporter.SelfObservable)
+	if !ok {
+		return
+	}
+	observable.SetSelfObserver(observer)
+	for _, dataType := range selfObservabilityDataTypes {
+		queueType := dataType
+		labels := map[string]string{"type": queueType}
+		t.registerMetrics(reporterQueueDepthMeterName, labels, newGauge(reporterQueueDepthMeterName, labels, func() float64 {
+			return float64(observable.QueueDepth(queueType))
+		}))
+	}
+}
+
+func (o *selfObserver) contextCreated(segment *RootSegmentSpan) {
+	if o == nil {
+		return
+	}
+	o.createdContexts.Inc(1)
+	o.activeContexts.Store(segment, time.Now())
+}
+
+func (o *selfObserver) contextFinished(segment *RootSegmentSpan) {
+	if o == nil {
+		return
+	}
+	o.finishedContexts.Inc(1)
+	o.activeContexts.Delete(segment)
+}
+
+// checkLeakedContexts counts the tracing contexts not finished for a long time, each context is counted once
+func (o *selfObserver) checkLeakedContexts() {
+	o.activeContexts.Range(func(key, value interface{}) bool {
+		if time.Since(value.(time.Time)) > possibleLeakedContextThreshold {
+			o.activeContexts.Delete(key)
+			o.leakedContexts.Inc(1)
+		}
+		return true
+	})
+}
+
+func (o *selfObserver) Dropped(dataType, reason string, count int) {
+	key := dataType + ":" + reason
+	counter, ok := o.droppedCounters.Load(key)
+	if !ok {
+		labels := map[string]string{"type": dataType, "reason": reason}
+		var loaded bool
+		if counter, loaded = o.droppedCounters.LoadOrStore(key, newCounter(reporterDroppedMeterName, labels, 0)); !loaded {
+			o.tracer.registerMetrics(reporterDroppedMeterName, labels, counter)
+		}
+	}
+	counter.(*counterImpl).Inc(float64(count))
+}
+
+func (o *selfObserver) Reconnected(dataType string) {
+	if counter := o.reconnectCounters[dataType]; counter != nil {
+		counter.Inc(1)
+	}
+}
+
+func (o *selfObserver) Sent(dataType string, latency time.Duration) {
+	if histogram := o.sendLatencies[dataType]; histogram != nil {
+		histogram.Observe(float64(latency.Milliseconds()))
+	}
+}
@@ -0,0 +1,88 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type selfObservableReporter struct {
+	StoreReporter
+	reporter.SelfObserverHolder
+}
+
+func (r *selfObservableReporter) QueueDepth(dataType string) int {
+	if dataType == reporter.DataTypeSegment {
+		return 3
+	}
+	return 0
+}
+
+func TestSelfObservability(t *testing.T) {
+	rep := &selfObservableReporter{}
+	tracer := &Tracer{initFlag: 1, Reporter: rep, Log: &LogWrapper{newDefaultLogger()}, meterMap: &sync.Map{}}
+	tracer.initSelfObservability()
+	observer := tracer.selfObserver
+
+	rep.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonQueueFull, 2)
+	rep.ObserveDropped(reporter.DataTypeSegment, reporter.DropReasonQueueFull, 1)
+	rep.ObserveDropped(reporter.DataTypeLog, reporter.DropReasonSendFailure, 1)
+	rep.ObserveReconnected(reporter.DataTypeSegment)
+	rep.ObserveSent(reporter.DataTypeMeter, time.Now())
+
+	finished, leaked := &RootSegmentSpan{}, &RootSegmentSpan{}
+	observer.contextCreated(finished)
+	observer.contextCreated(leaked)
+	observer.contextFinished(finished)
+	observer.activeContexts.Store(leaked, time.Now().Add(-possibleLeakedContextThreshold-time.Second))
+
+	tracer.sendMetrics()
+	values := make(map[string]float64)
+	histograms := make(map[string]int64)
+	for _, m := range rep.Metrics {
+		key := m.Name() + "{" + m.Labels()["type"] + "," + m.Labels()["reason"] + "}"
+		switch meter := m.(type) {
+		case reporter.ReportedMeterSingleValue:
+			values[key] = meter.Value()
+		case reporter.ReportedMeterHistogram:
+			for _, b := range meter.BucketValues() {
+				histograms[key] += b.Count()
+			}
+		}
+	}
+	assert.Equal(t, float64(2), values[createdTracingContextMeterName+"{,}"])
+	assert.Equal(t, float64(1), values[finishedTracingContextMeterName+"{,}"])
+	assert.Equal(t, float64(1), values[possibleLeakedContextMeterName+"{,}"])
+	assert.Equal(t, float64(3), values[reporterQueueDepthMeterName+"{segment,}"])
+	assert.Equal(t, float64(0), values[reporterQueueDepthMeterName+"{log,}"])
+	assert.Equal(t, float64(3), values[reporterDroppedMeterName+"{segment,queue_full}"])
+	assert.Equal(t, float64(1), values[reporterDroppedMeterName+"{log,send_failure}"])
+	assert.Equal(t, float64(1), values[reporterReconnectMeterName+"{segment,}"])
+	assert.Equal(t, int64(1), histograms[reporterSendLatencyMeterName+"{meter,}"])
+	assert.Equal(t, int64(0), histograms[reporterSendLatencyMeterName+"{log,}"])
+
+	// the leaked context should be counted only once
+	observer.checkLeakedContexts()
+	assert.Equal(t, float64(1), observer.leakedContexts.Get())
+}
@@ -0,0 +1,80 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// InitShutdown configures how the tracer flushes the pending data when the application exits,
+// the signal hook would flush the data when the process receives SIGINT or SIGTERM, and keeps tracing
+// while the application handles the signal by itself, such as the graceful shutdown.
+func (t *Tracer) InitShutdown(timeoutSecond int, signalHook bool) {
+	t.shutdownTimeout = time.Duration(timeoutSecond) * time.Second
+	if signalHook && t.InitSuccess() {
+		t.watchShutdownSignal()
+	}
+}
+
+// ShutdownTracer stops tracing, then flushes the pending data of reporter in the timeout.
+// It is invoked when the main function returned.
+func (t *Tracer) ShutdownTracer() {
+	if !atomic.CompareAndSwapInt32(&t.initFlag, 1, 2) {
+		return
+	}
+	// collect the metrics at the last time
+	t.sendMetrics()
+	ctx, cancel := context.WithTimeout(context.Background(), t.shutdownTimeout)
+	defer cancel()
+	if err := t.Reporter.Shutdown(ctx); err != nil {
+		t.Log.Warnf("flush the pending data of reporter failure: %v", err)
+	}
+}
+
+// FlushTracer flushes the metrics and the pending data of reporter in the timeout, the tracer keeps working.
+// It is invoked when the process receives the terminate signal, so the data would be kept
+// even if the process is killed before the main function returned.
+func (t *Tracer) FlushTracer() {
+	if !t.InitSuccess() {
+		return
+	}
+	t.sendMetrics()
+	ctx, cancel := context.WithTimeout(context.Background(), t.shutdownTimeout)
+	defer cancel()
+	if err := t.Reporter.Flush(ctx); err != nil {
+		t.Log.Warnf("flush the pending data of reporter failure: %v", err)
+	}
+}
+
+// watchShutdownSignal flushes the data when receiving the signals, the signals are still delivered to
+// the application's own handlers. Note that the process is not terminated by these signals after watching,
+// so it should be enabled only when the application handles them.
+func (t *Tracer) watchShutdownSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for range signals {
+			t.FlushTracer()
+		}
+	}()
+}
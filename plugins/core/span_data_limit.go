@@ -0,0 +1,120 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"unicode/utf8"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+)
+
+// the tag added to the span when its tags or logs are truncated by the limits
+const spanDataTruncatedTag = "span.truncated"
+
+// SpanDataLimitConfig is the limits of the tags and logs in one span, not limit when the value is not greater than 0
+type SpanDataLimitConfig struct {
+	MaxTagCount     int
+	MaxTagValueSize int
+	MaxLogCount     int
+	MaxLogValueSize int
+}
+
+// InitSpanDataLimit configures the limits of the tags and logs in every span.
+func (t *Tracer) InitSpanDataLimit(limit *SpanDataLimitConfig) {
+	t.spanDataLimit = limit
+}
+
+func (ds *DefaultSpan) dataLimit() *SpanDataLimitConfig {
+	if ds.tracer == nil || ds.tracer.spanDataLimit == nil {
+		return &SpanDataLimitConfig{}
+	}
+	return ds.tracer.spanDataLimit
+}
+
+func (ds *DefaultSpan) limitTagValue(value string) string {
+	return ds.limitValue(value, ds.dataLimit().MaxTagValueSize)
+}
+
+func (ds *DefaultSpan) limitLogValues(data []*commonv3.KeyStringValuePair) {
+	size := ds.dataLimit().MaxLogValueSize
+	for _, kv := range data {
+		kv.Value = ds.limitValue(kv.Value, size)
+	}
+}
+
+// limitValue truncates the value to the max size, and keeps the last rune complete
+func (ds *DefaultSpan) limitValue(value string, size int) string {
+	if size <= 0 || len(value) <= size {
+		return value
+	}
+	for size > 0 && !utf8.RuneStart(value[size]) {
+		size--
+	}
+	ds.markDataTruncated()
+	return value[:size]
+}
+
+// reachTagLimit checks the count of tags has reached the limit, the indicator tags of agent are not counted
+func (ds *DefaultSpan) reachTagLimit() bool {
+	limit := ds.dataLimit().MaxTagCount
+	if limit <= 0 {
+		return false
+	}
+	count := 0
+	for _, tag := range ds.Tags {
+		if !isIndicatorTag(tag.Key) {
+			count++
+		}
+	}
+	if count < limit {
+		return false
+	}
+	ds.markDataTruncated()
+	return true
+}
+
+func (ds *DefaultSpan) reachLogLimit() bool {
+	limit := ds.dataLimit().MaxLogCount
+	if limit <= 0 || len(ds.Logs) < limit {
+		return false
+	}
+	ds.markDataTruncated()
+	return true
+}
+
+func (ds *DefaultSpan) markDataTruncated() {
+	if ds.dataTruncated {
+		return
+	}
+	ds.dataTruncated = true
+	ds.indicatorTag(spanDataTruncatedTag)
+}
+
+// indicatorTag adds the tag with "true" value, which bypasses the limits
+func (ds *DefaultSpan) indicatorTag(key string) {
+	for _, tag := range ds.Tags {
+		if tag.Key == key {
+			return
+		}
+	}
+	ds.Tags = append(ds.Tags, &commonv3.KeyStringValuePair{Key: key, Value: "true"})
+}
+
+func isIndicatorTag(key string) bool {
+	return key == spanDataTruncatedTag || key == spanLimitTruncatedTag
+}
@@ -18,16 +18,27 @@
 package core
 
 import (
+	"bytes"
+	"fmt"
 	"math"
+	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/apache/skywalking-go/plugins/core/reporter"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
 
 	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
 	agentv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
 )
 
+const (
+	// maxErrorStackSize is the max size of the stack recorded by RecordError
+	maxErrorStackSize         = 4096
+	errorStackTruncatedSuffix = "\n...(truncated)"
+)
+
 type DefaultSpan struct {
 	Refs          []reporter.SpanContext
 	tracer        *Tracer
@@ -46,6 +57,15 @@ type DefaultSpan struct {
 	InAsyncMode       bool
 	AsyncModeFinished bool
 	AsyncOpLocker     *sync.Mutex
+
+	// the active span restored when the span ended, for the span created from the context.Context
+	restoreActive TracingSpan
+	restoreOnEnd  bool
+
+	// the tags or logs are truncated by the span data limits
+	dataTruncated bool
+	// the span is captured by the snapshot, the segments in other goroutines reference it
+	captured bool
 }
 
 func NewDefaultSpan(tracer *Tracer, parent TracingSpan) *DefaultSpan {
@@ -111,12 +131,16 @@ func (ds *DefaultSpan) Tag(key, value string) {
 		ds.AsyncOpLocker.Lock()
 		defer ds.AsyncOpLocker.Unlock()
 	}
+	value = ds.limitTagValue(value)
 	for _, tag := range ds.Tags {
 		if tag.Key == key {
 			tag.Value = value
 			return
 		}
 	}
+	if ds.reachTagLimit() {
+		return
+	}
 	ds.Tags = append(ds.Tags, &commonv3.KeyStringValuePair{Key: key, Value: value})
 }
 
@@ -125,6 +149,13 @@ func (ds *DefaultSpan) Log(ll ...string) {
 		ds.AsyncOpLocker.Lock()
 		defer ds.AsyncOpLocker.Unlock()
 	}
+	ds.log0(ll...)
+}
+
+func (ds *DefaultSpan) log0(ll ...string) {
+	if ds.reachLogLimit() {
+		return
+	}
 	data := make([]*commonv3.KeyStringValuePair, 0, int32(math.Ceil(float64(len(ll))/2.0)))
 	var kvp *commonv3.KeyStringValuePair
 	for i, l := range ll {
@@ -136,27 +167,116 @@ func (ds *DefaultSpan) Log(ll ...string) {
 			kvp.Value = l
 		}
 	}
+	ds.limitLogValues(data)
 	ds.Logs = append(ds.Logs, &agentv3.Log{Time: Millisecond(time.Now()), Data: data})
 }
 
+// LogFields add the typed key/value pairs as a log, the log time is the given time.Time or the milliseconds,
+// or the current time when the timestamp is absent
+func (ds *DefaultSpan) LogFields(timestamp interface{}, kv ...interface{}) {
+	data := make([]*commonv3.KeyStringValuePair, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok || key == "" {
+			continue
+		}
+		data = append(data, &commonv3.KeyStringValuePair{Key: key, Value: formatLogValue(kv[i+1])})
+	}
+	logTime := Millisecond(time.Now())
+	switch t := timestamp.(type) {
+	case time.Time:
+		if !t.IsZero() {
+			logTime = Millisecond(t)
+		}
+	case int64:
+		if t > 0 {
+			logTime = t
+		}
+	}
+	if ds.InAsyncMode {
+		ds.AsyncOpLocker.Lock()
+		defer ds.AsyncOpLocker.Unlock()
+	}
+	if ds.reachLogLimit() {
+		return
+	}
+	ds.limitLogValues(data)
+	ds.Logs = append(ds.Logs, &agentv3.Log{Time: logTime, Data: data})
+}
+
 func (ds *DefaultSpan) Error(ll ...string) {
 	if ds.InAsyncMode {
 		ds.AsyncOpLocker.Lock()
 		defer ds.AsyncOpLocker.Unlock()
 	}
 	ds.IsError = true
-	ds.Log(ll...)
+	ds.log0(ll...)
+}
+
+func (ds *DefaultSpan) RecordError(err error, stack []byte) {
+	if err == nil {
+		return
+	}
+	ll := []string{"event", "error", "error.kind", reflect.TypeOf(err).String(), "message", err.Error()}
+	if len(stack) > 0 {
+		ll = append(ll, "stack", truncateErrorStack(stack))
+	}
+	ds.Error(ll...)
+}
+
+// truncateErrorStack keeps the frames in the size limitation, the deeper frames are dropped
+func truncateErrorStack(stack []byte) string {
+	if len(stack) <= maxErrorStackSize {
+		return string(stack)
+	}
+	end := bytes.LastIndexByte(stack[:maxErrorStackSize], '\n')
+	if end <= 0 {
+		end = maxErrorStackSize
+	}
+	return string(stack[:end]) + errorStackTruncatedSuffix
+}
+
+// AddRef links the span to the upstream context extracted from the carrier, the duplicate link is ignored
+func (ds *DefaultSpan) AddRef(extractor interface{}) error {
+	ref := &SpanContext{}
+	if err := ref.Decode(extractor.(tracing.ExtractorWrapper).Fun()); err != nil {
+		return err
+	}
+	if !ref.Valid {
+		return nil
+	}
+	if ds.InAsyncMode {
+		ds.AsyncOpLocker.Lock()
+		defer ds.AsyncOpLocker.Unlock()
+	}
+	for _, exist := range ds.Refs {
+		if exist.GetTraceID() == ref.TraceID && exist.GetParentSegmentID() == ref.ParentSegmentID &&
+			exist.GetParentSpanID() == ref.ParentSpanID {
+			return nil
+		}
+	}
+	ds.Refs = append(ds.Refs, ref)
+	return nil
 }
 
 func (ds *DefaultSpan) End(changeParent bool) {
 	ds.EndTime = time.Now()
 	if changeParent {
 		if ctx := getTracingContext(); ctx != nil {
-			ctx.SaveActiveSpan(ds.Parent)
+			if ds.restoreOnEnd {
+				ctx.SaveActiveSpan(ds.restoreActive)
+			} else {
+				ctx.SaveActiveSpan(ds.Parent)
+			}
 		}
 	}
 }
 
+func (ds *DefaultSpan) restoreActiveOnEnd(previous TracingSpan) {
+	ds.restoreActive = previous
+	ds.restoreOnEnd = true
+}
+
 func (ds *DefaultSpan) IsEntry() bool {
 	return ds.SpanType == SpanTypeEntry
 }
@@ -202,3 +322,32 @@ func (ds *DefaultSpan) GetEndPointName() string {
 func (ds *DefaultSpan) GetParentSpan() interface{} {
 	return ds.Parent
 }
+
+func formatLogValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case int:
+		return strconv.Itoa(val)
+	case int32:
+		return strconv.FormatInt(int64(val), 10)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10)
+	case uint64:
+		return strconv.FormatUint(val, 10)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case error:
+		return val.Error()
+	default:
+		return fmt.Sprint(val)
+	}
+}
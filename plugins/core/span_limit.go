@@ -0,0 +1,57 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"sync/atomic"
+)
+
+// the tag added to the first span of segment when the spans over the limit are dropped
+const spanLimitTruncatedTag = "segment.truncated"
+
+// InitSpanLimit configures the max count of spans in one segment, not limit when it's not greater than 0.
+func (t *Tracer) InitSpanLimit(spanLimitPerSegment int) {
+	t.spanLimitPerSegment = int32(spanLimitPerSegment)
+}
+
+// reachSpanLimit checks the segment of parent span is full or not, and marks the segment truncated when it's full
+func (t *Tracer) reachSpanLimit(parent SegmentSpan) bool {
+	if t.spanLimitPerSegment <= 0 {
+		return false
+	}
+	segmentContext := parent.GetSegmentContext()
+	if segmentContext.spanIDGenerator == nil || segmentContext.truncated == nil {
+		return false
+	}
+	// the span ID starts from 0, so the count of created spans is the latest span ID + 1
+	if atomic.LoadInt32(segmentContext.spanIDGenerator)+1 < t.spanLimitPerSegment {
+		return false
+	}
+	atomic.StoreInt32(segmentContext.truncated, 1)
+	return true
+}
+
+func (rs *RootSegmentSpan) tagIfTruncated() {
+	if rs.truncated != nil && atomic.LoadInt32(rs.truncated) == 1 {
+		if rs.InAsyncMode {
+			rs.AsyncOpLocker.Lock()
+			defer rs.AsyncOpLocker.Unlock()
+		}
+		rs.indicatorTag(spanLimitTruncatedTag)
+	}
+}
@@ -25,6 +25,11 @@ const noopContextValue = "N/A"
 
 type NoopSpan struct {
 	stackCount int
+	// the parent span when the segment reached the span limit, for propagating the context
+	parent SegmentSpan
+	// the active span restored when the span ended, for the span created from the context.Context
+	restoreActive TracingSpan
+	restoreOnEnd  bool
 }
 
 func newSnapshotNoopSpan() *NoopSpan {
@@ -40,15 +45,31 @@ func newNoopSpan() *NoopSpan {
 	}
 }
 
-func (*NoopSpan) GetTraceID() string {
+func newPropagatingNoopSpan(parent SegmentSpan) *NoopSpan {
+	return &NoopSpan{
+		stackCount: 1,
+		parent:     parent,
+	}
+}
+
+func (n *NoopSpan) GetTraceID() string {
+	if n.parent != nil {
+		return n.parent.GetTraceID()
+	}
 	return noopContextValue
 }
 
-func (*NoopSpan) GetSegmentID() string {
+func (n *NoopSpan) GetSegmentID() string {
+	if n.parent != nil {
+		return n.parent.GetSegmentID()
+	}
 	return noopContextValue
 }
 
-func (*NoopSpan) GetSpanID() int32 {
+func (n *NoopSpan) GetSpanID() int32 {
+	if n.parent != nil {
+		return n.parent.GetSpanID()
+	}
 	return -1
 }
 
@@ -89,6 +110,16 @@ func (*NoopSpan) Log(...string) {
 func (*NoopSpan) Error(...string) {
 }
 
+func (*NoopSpan) LogFields(interface{}, ...interface{}) {
+}
+
+func (*NoopSpan) RecordError(error, []byte) {
+}
+
+func (*NoopSpan) AddRef(interface{}) error {
+	return nil
+}
+
 func (n *NoopSpan) enterNoSpan() {
 	n.stackCount++
 }
@@ -97,11 +128,23 @@ func (n *NoopSpan) End() {
 	n.stackCount--
 	if n.stackCount == 0 {
 		if ctx := getTracingContext(); ctx != nil {
-			ctx.SaveActiveSpan(nil)
+			switch {
+			case n.restoreOnEnd:
+				ctx.SaveActiveSpan(n.restoreActive)
+			case n.parent != nil:
+				ctx.SaveActiveSpan(n.parent)
+			default:
+				ctx.SaveActiveSpan(nil)
+			}
 		}
 	}
 }
 
+func (n *NoopSpan) restoreActiveOnEnd(previous TracingSpan) {
+	n.restoreActive = previous
+	n.restoreOnEnd = true
+}
+
 func (*NoopSpan) IsEntry() bool {
 	return false
 }
@@ -58,8 +58,11 @@ type SegmentContext struct {
 	collect            chan<- reporter.ReportedSpan
 	refNum             *int32
 	spanIDGenerator    *int32
+	truncated          *int32
 	FirstSpan          TracingSpan `json:"-"`
 	CorrelationContext map[string]string
+	// the segment is recorded for propagating the context or tail sampling, but not sampled by the sampler
+	unsampled bool
 }
 
 func (c *SegmentContext) GetTraceID() string {
@@ -236,6 +239,8 @@ type RootSegmentSpan struct {
 	notify  <-chan reporter.ReportedSpan
 	segment []reporter.ReportedSpan
 	doneCh  chan int32
+	// the segment is sampled by the sampler or the upstream, the segment not sampled is only reported by the tail sampling
+	headSampled bool
 }
 
 func (rs *RootSegmentSpan) End() {
@@ -267,6 +272,8 @@ func (rs *RootSegmentSpan) createRootSegmentContext(ctx *TracingContext, _ Segme
 	}
 	i := int32(0)
 	rs.spanIDGenerator = &i
+	var truncated int32
+	rs.truncated = &truncated
 	rs.SpanID = i
 	rs.ParentSpanID = -1
 	return
@@ -281,6 +288,11 @@ func (s *SnapshotSpan) GetDefaultSpan() *DefaultSpan {
 	return &s.DefaultSpan
 }
 
+// IsEntry returns false, the snapshot span cannot be reused as the entry span in other goroutine
+func (s *SnapshotSpan) IsEntry() bool {
+	return false
+}
+
 func (s *SnapshotSpan) End() {
 	panic(fmt.Errorf("cannot End the span in other goroutine"))
 }
@@ -305,10 +317,22 @@ func (s *SnapshotSpan) Log(_ ...string) {
 	panic(fmt.Errorf("cannot add log of span in other goroutine"))
 }
 
+func (s *SnapshotSpan) LogFields(interface{}, ...interface{}) {
+	panic(fmt.Errorf("cannot add log of span in other goroutine"))
+}
+
 func (s *SnapshotSpan) Error(_ ...string) {
 	panic(fmt.Errorf("cannot add error of span in other goroutine"))
 }
 
+func (s *SnapshotSpan) RecordError(error, []byte) {
+	panic(fmt.Errorf("cannot add error of span in other goroutine"))
+}
+
+func (s *SnapshotSpan) AddRef(interface{}) error {
+	panic(fmt.Errorf("cannot add ref of span in other goroutine"))
+}
+
 func (s *SnapshotSpan) GetSegmentContext() SegmentContext {
 	return s.SegmentContext
 }
@@ -348,6 +372,7 @@ func newSegmentRoot(segmentSpan *SegmentSpanImpl) *RootSegmentSpan {
 	s.notify = ch
 	s.segment = make([]reporter.ReportedSpan, 0, 10)
 	s.doneCh = make(chan int32)
+	s.tracer().selfObserver.contextCreated(s)
 	go func() {
 		total := -1
 		defer close(ch)
@@ -363,7 +388,13 @@ func newSegmentRoot(segmentSpan *SegmentSpanImpl) *RootSegmentSpan {
 				break
 			}
 		}
-		s.tracer().Reporter.SendTracing(append(s.segment, s))
+		s.tagIfTruncated()
+		if s.tracer().tailSampling.keep(s) {
+			if spans := s.tracer().segmentProcessors.process(append(s.segment, s)); len(spans) > 0 {
+				s.tracer().Reporter.SendTracing(spans)
+			}
+		}
+		s.tracer().selfObserver.contextFinished(s)
 	}()
 	return s
 }
@@ -372,7 +403,11 @@ func newSnapshotSpan(current TracingSpan) TracingSpan {
 	if current == nil {
 		return nil
 	}
-	if _, isNoop := current.(*NoopSpan); isNoop {
+	if noop, isNoop := current.(*NoopSpan); isNoop {
+		// the noop span over the span limit keeps the context of segment
+		if noop.parent != nil {
+			return newSnapshotSpan(noop.parent)
+		}
 		return newSnapshotNoopSpan()
 	}
 	segmentSpan, ok := current.(SegmentSpan)
@@ -380,6 +415,7 @@ func newSnapshotSpan(current TracingSpan) TracingSpan {
 		return nil
 	}
 
+	segmentSpan.GetDefaultSpan().captured = true
 	segCtx := segmentSpan.GetSegmentContext()
 	copiedCorrelation := make(map[string]string)
 	for k, v := range segCtx.CorrelationContext {
@@ -399,6 +435,8 @@ func newSnapshotSpan(current TracingSpan) TracingSpan {
 			collect:            segCtx.collect,
 			refNum:             segCtx.refNum,
 			spanIDGenerator:    segCtx.spanIDGenerator,
+			truncated:          segCtx.truncated,
+			unsampled:          segCtx.unsampled,
 			FirstSpan:          segCtx.FirstSpan,
 			CorrelationContext: copiedCorrelation,
 		},
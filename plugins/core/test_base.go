@@ -18,7 +18,9 @@
 package core
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/reporter"
@@ -46,7 +48,7 @@ func init() {
 func ResetTracingContext() {
 	SetGLS(nil)
 	Tracing = &Tracer{initFlag: 1, Sampler: NewConstSampler(true), Reporter: &StoreReporter{},
-		ServiceEntity: NewEntity("test", "test-instance"), meterMap: &sync.Map{}}
+		ServiceEntity: NewEntity("test", "test-instance"), meterMap: &sync.Map{}, Log: &LogWrapper{newDefaultLogger()}}
 	SetAsNewGoroutine()
 	ReportConnectionStatus = reporter.ConnectionStatusConnected
 }
@@ -65,6 +67,22 @@ func GetReportedSpans() []reporter.ReportedSpan {
 	return Tracing.Reporter.(*StoreReporter).Spans
 }
 
+// WaitReportedSpans waits until the count of reported spans reached or timeout, returns the reported spans
+func WaitReportedSpans(count int) []reporter.ReportedSpan {
+	for deadline := time.Now().Add(time.Second); len(GetReportedSpans()) < count && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return GetReportedSpans()
+}
+
+// NewTestHeader builds the propagated header of the upstream context in the trace, for testing the entry span
+func NewTestHeader(traceID string) string {
+	upstream := &SpanContext{Sample: 1, TraceID: traceID, ParentSegmentID: traceID + "-segment",
+		ParentService: "producer", ParentServiceInstance: "producer-instance", ParentEndpoint: "producer-endpoint",
+		AddressUsedAtClient: "localhost:8080"}
+	return upstream.EncodeSW8()
+}
+
 type StoreReporter struct {
 	Spans   []reporter.ReportedSpan
 	Metrics []reporter.ReportedMeter
@@ -96,3 +114,11 @@ func (r *StoreReporter) ConnectionStatus() reporter.ConnectionStatus {
 
 func (r *StoreReporter) Close() {
 }
+
+func (r *StoreReporter) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (r *StoreReporter) Shutdown(ctx context.Context) error {
+	return nil
+}
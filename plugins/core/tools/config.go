@@ -0,0 +1,106 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tools
+
+import "github.com/apache/skywalking-go/plugins/core/operator"
+
+// WatchConfig registers the config which could be changed by the backend through the dynamic configuration,
+// the apply function is invoked with the new value, or with the default value when the config is deleted.
+// The current value is kept when the apply function returns an error.
+func WatchConfig(key, defaultValue string, apply func(value string) error) {
+	if key == "" || apply == nil {
+		return
+	}
+	op := operator.GetOperator()
+	if op == nil {
+		return
+	}
+	op.Tools().(operator.ToolsOperator).WatchConfig(key, defaultValue, apply)
+}
+
+// DynamicValue holds the value of the plugin config which could be changed through the dynamic configuration,
+// it's safe to read the value when the config is changing.
+// The config field should be declared as *DynamicValue with the value type in the tag, such as `dynamic:"bool"`.
+type DynamicValue struct {
+	value atomicValue
+}
+
+type atomicValue interface {
+	Load() interface{}
+	Store(val interface{})
+}
+
+// NewDynamicValue creates the holder of the dynamic config with the initial value
+func NewDynamicValue(val interface{}) *DynamicValue {
+	var value atomicValue = &defaultAtomicValue{}
+	if op := operator.GetOperator(); op != nil {
+		value = op.Tools().(operator.ToolsOperator).NewAtomicValue().(atomicValue)
+	}
+	value.Store(val)
+	return &DynamicValue{value: value}
+}
+
+// Store changes the value, the type of value should not be changed
+func (d *DynamicValue) Store(val interface{}) {
+	d.value.Store(val)
+}
+
+func (d *DynamicValue) Bool() bool {
+	v, _ := d.load().(bool)
+	return v
+}
+
+func (d *DynamicValue) String() string {
+	v, _ := d.load().(string)
+	return v
+}
+
+func (d *DynamicValue) Int() int {
+	v, _ := d.load().(int)
+	return v
+}
+
+func (d *DynamicValue) Float64() float64 {
+	v, _ := d.load().(float64)
+	return v
+}
+
+func (d *DynamicValue) StringArray() []string {
+	v, _ := d.load().([]string)
+	return v
+}
+
+// load returns nil when the config is not initialized, such as running without the agent
+func (d *DynamicValue) load() interface{} {
+	if d == nil {
+		return nil
+	}
+	return d.value.Load()
+}
+
+type defaultAtomicValue struct {
+	value interface{}
+}
+
+func (d *defaultAtomicValue) Load() interface{} {
+	return d.value
+}
+
+func (d *defaultAtomicValue) Store(val interface{}) {
+	d.value = val
+}
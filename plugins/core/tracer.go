@@ -18,12 +18,15 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	defLog "log"
 	"os"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/reporter"
@@ -42,19 +45,43 @@ type CorrelationConfig struct {
 type Tracer struct {
 	ServiceEntity *reporter.Entity
 	Reporter      reporter.Reporter
-	// 0 not init 1 init
+	// 0 not init 1 init 2 shutdown
 	initFlag    int32
 	Sampler     Sampler
 	Log         *LogWrapper
-	correlation *CorrelationConfig
+	correlation atomic.Value
 	cdsWatchers []reporter.AgentConfigChangeWatcher
+	// merge the dynamic configs from the backend and the local file
+	configSources *configSources
 	// for plugin tools
 	tools *TracerTools
 	// for all metrics
 	meterMap              *sync.Map
 	meterCollectListeners []func()
-	ignoreSuffix          []string
-	traceIgnorePath       []string
+	// the []string values, could be changed by the dynamic configuration
+	ignoreSuffix    atomic.Value
+	traceIgnorePath atomic.Value
+	// the max waiting time of flushing the pending data when shutdown
+	shutdownTimeout time.Duration
+	// for the meters of agent itself
+	selfObserver *selfObserver
+	// the max count of spans in one segment, the spans over the limit are noop
+	spanLimitPerSegment int32
+	// keep tracing and propagating the context when the backend is disconnected
+	keepTracingWhenDisconnected bool
+	// the limits of the tags and logs in one span
+	spanDataLimit *SpanDataLimitConfig
+	// keep the error and slow segments when it's enabled
+	tailSampling *tailSampling
+	// follow the sampling decision of the upstream, and propagate the "not sampled" flag
+	honorUpstreamSampling bool
+	// stop reporting the logs, or the logs lower than the minimum level
+	logReportDisabled int32
+	logReportLevel    atomic.Value
+	// change or drop the spans of the finished segment before reporting
+	segmentProcessors segmentProcessorChain
+	// normalize the names of the entry spans to reduce the cardinality of endpoints
+	endpointNormalizer endpointNormalizer
 }
 
 func (t *Tracer) Init(entity *reporter.Entity, rep reporter.Reporter, samp Sampler, logger operator.LogOperator,
@@ -65,18 +92,21 @@ func (t *Tracer) Init(entity *reporter.Entity, rep reporter.Reporter, samp Sampl
 	if logger != nil && !reflect.ValueOf(logger).IsZero() {
 		t.Log.ChangeLogger(logger)
 	}
-	t.Reporter.Boot(entity, t.cdsWatchers)
-	t.initFlag = 1
-	t.initMetricsCollect(meterCollectSecond)
-	t.correlation = correlation
-	t.ignoreSuffix = strings.Split(ignoreSuffixStr, ",")
-	t.traceIgnorePath = strings.Split(ignorePath, ",")
-	// notify the tracer been init success
+	t.initSelfObservability()
+	t.correlation.Store(correlation)
+	t.ignoreSuffix.Store(strings.Split(ignoreSuffixStr, ","))
+	t.traceIgnorePath.Store(strings.Split(ignorePath, ","))
+	// notify the tracer been init, the plugins could register the config watchers before booting the reporter
 	if len(GetInitNotify()) > 0 {
 		for _, fun := range GetInitNotify() {
 			fun()
 		}
 	}
+	t.initConfigWatchers(ignoreSuffixStr, ignorePath)
+	t.configSources = newConfigSources(t.cdsWatchers)
+	t.Reporter.Boot(entity, t.configSources.sourceWatchers(configSourceBackend))
+	atomic.StoreInt32(&t.initFlag, 1)
+	t.initMetricsCollect(meterCollectSecond)
 	return nil
 }
 
@@ -121,7 +151,7 @@ func newTracer() *Tracer {
 }
 
 func (t *Tracer) InitSuccess() bool {
-	return t.initFlag == 1
+	return atomic.LoadInt32(&t.initFlag) == 1
 }
 
 func (t *Tracer) ChangeLogger(logger interface{}) {
@@ -155,6 +185,16 @@ func (e *emptyReporter) ConnectionStatus() reporter.ConnectionStatus {
 func (e *emptyReporter) Close() {
 }
 
+// nolint
+func (e *emptyReporter) Flush(ctx context.Context) error {
+	return nil
+}
+
+// nolint
+func (e *emptyReporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
 type LogWrapper struct {
 	Logger operator.LogOperator
 }
@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -62,3 +63,37 @@ func verifyEntity(t *testing.T, entity *reporter.Entity, serviceName, instanceNa
 		assert.NotEmpty(t, p.Value, "prop value is empty")
 	}
 }
+
+type shutdownTestReporter struct {
+	StoreReporter
+	flushed  int
+	shutdown int
+}
+
+func (r *shutdownTestReporter) Flush(ctx context.Context) error {
+	r.flushed++
+	return nil
+}
+
+func (r *shutdownTestReporter) Shutdown(ctx context.Context) error {
+	r.shutdown++
+	return nil
+}
+
+func TestShutdownTracer(t *testing.T) {
+	defer ResetTracingContext()
+	rep := &shutdownTestReporter{}
+	Tracing.Reporter = rep
+	Tracing.InitShutdown(1, false)
+
+	Tracing.FlushTracer()
+	assert.Equal(t, 1, rep.flushed)
+	assert.True(t, Tracing.InitSuccess(), "the tracer should keep working after flushed")
+
+	Tracing.ShutdownTracer()
+	Tracing.ShutdownTracer()
+	assert.Equal(t, 1, rep.shutdown, "the reporter should be shutdown only once")
+	assert.False(t, Tracing.InitSuccess(), "the tracer should stop working after shutdown")
+	Tracing.FlushTracer()
+	assert.Equal(t, 1, rep.flushed, "the tracer should not flush after shutdown")
+}
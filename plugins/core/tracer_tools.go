@@ -23,10 +23,15 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"unsafe"
+
+	"github.com/apache/skywalking-go/plugins/core/reporter"
 )
 
 type TracerTools struct {
+	// the watchers of the plugin configs, registered to the tracer when booting
+	configWatchers []reporter.AgentConfigChangeWatcher
 }
 
 func NewTracerTools() *TracerTools {
@@ -109,10 +114,20 @@ func (t *TracerTools) Atoi(val string) (int, error) {
 	return strconv.Atoi(val)
 }
 
+// WatchConfig registers the plugin config which could be changed by the backend,
+// the apply function is invoked with the new value, or the default value when the config is deleted
+func (t *TracerTools) WatchConfig(key, defaultValue string, apply func(value string) error) {
+	t.configWatchers = append(t.configWatchers, newConfigWatcher(key, defaultValue, apply))
+}
+
 func (t *TracerTools) NewSyncMap() interface{} {
 	return newSyncMap()
 }
 
+func (t *TracerTools) NewAtomicValue() interface{} {
+	return &atomic.Value{}
+}
+
 func (t *TracerTools) checkFieldSupport(field reflect.Value, instanceField *reflect.StructField, filter *ReflectFieldFilter) bool {
 	if filter.name != "" {
 		if instanceField.Name != filter.name {
@@ -42,10 +42,12 @@ func (t *Tracer) DebugStack() []byte {
 }
 
 func (t *Tracer) CreateEntrySpan(operationName string, extractor interface{}, opts ...interface{}) (s interface{}, err error) {
+	// the ignore paths are matched by the raw operation name
 	ctx, tracingSpan, noop := t.createNoop(operationName)
 	if noop {
 		return tracingSpan, nil
 	}
+	operationName = t.endpointNormalizer.normalize(operationName)
 	defer func() {
 		saveSpanToActiveIfNotError(ctx, s, err)
 	}()
@@ -80,6 +82,12 @@ func (t *Tracer) CreateLocalSpan(operationName string, opts ...interface{}) (s i
 func (t *Tracer) CreateExitSpan(operationName, peer string, injector interface{}, opts ...interface{}) (s interface{}, err error) {
 	ctx, tracingSpan, noop := t.createNoop(operationName)
 	if noop {
+		// the noop span over the span limit still propagates the context to the downstream
+		if noopSpan, ok := tracingSpan.(*NoopSpan); ok && noopSpan.parent != nil {
+			if err := t.injectSpanContext(noopSpan.parent, peer, injector); err != nil {
+				return nil, err
+			}
+		}
 		return tracingSpan, nil
 	}
 	defer func() {
@@ -94,28 +102,35 @@ func (t *Tracer) CreateExitSpan(operationName, peer string, injector interface{}
 	if err != nil {
 		return nil, err
 	}
-	spanContext := &SpanContext{}
 	reportedSpan, ok := span.(SegmentSpan)
 	if !ok {
 		return nil, errors.New("span type is wrong")
 	}
+	if err = t.injectSpanContext(reportedSpan, peer, injector); err != nil {
+		return nil, err
+	}
+	return span, nil
+}
 
-	firstSpan := reportedSpan.GetSegmentContext().FirstSpan
+func (t *Tracer) injectSpanContext(span SegmentSpan, peer string, injector interface{}) error {
+	spanContext := &SpanContext{}
+	firstSpan := span.GetSegmentContext().FirstSpan
 	spanContext.Sample = 1
-	spanContext.TraceID = reportedSpan.GetSegmentContext().TraceID
-	spanContext.ParentSegmentID = reportedSpan.GetSegmentContext().SegmentID
-	spanContext.ParentSpanID = reportedSpan.GetSegmentContext().SpanID
+	// the segment not sampled by head could still be kept by the tail sampling when it finished,
+	// so the downstream should keep recording, otherwise the kept trace is broken when honor the upstream sampling
+	if span.GetSegmentContext().unsampled && t.tailSampling == nil {
+		spanContext.Sample = 0
+	}
+	spanContext.TraceID = span.GetSegmentContext().TraceID
+	spanContext.ParentSegmentID = span.GetSegmentContext().SegmentID
+	spanContext.ParentSpanID = span.GetSegmentContext().SpanID
 	spanContext.ParentService = t.ServiceEntity.ServiceName
 	spanContext.ParentServiceInstance = t.ServiceEntity.ServiceInstanceName
 	spanContext.ParentEndpoint = firstSpan.GetOperationName()
 	spanContext.AddressUsedAtClient = peer
-	spanContext.CorrelationContext = reportedSpan.GetSegmentContext().CorrelationContext
+	spanContext.CorrelationContext = span.GetSegmentContext().CorrelationContext
 
-	err = spanContext.Encode(injector.(tracing.InjectorWrapper).Fun())
-	if err != nil {
-		return nil, err
-	}
-	return span, nil
+	return spanContext.Encode(injector.(tracing.InjectorWrapper).Fun())
 }
 
 func (t *Tracer) ActiveSpan() interface{} {
@@ -180,6 +195,9 @@ func (t *Tracer) GetCorrelationContextValue(key string) string {
 	if span == nil {
 		return ""
 	}
+	if noop, ok := span.(*NoopSpan); ok && noop.parent != nil {
+		span = noop.parent
+	}
 	switch reportedSpan := span.(type) {
 	case *SegmentSpanImpl:
 		return reportedSpan.Context().GetCorrelationContextValue(key)
@@ -195,20 +213,24 @@ func (t *Tracer) SetCorrelationContextValue(key, value string) {
 	if span == nil {
 		return
 	}
+	if noop, ok := span.(*NoopSpan); ok && noop.parent != nil {
+		span = noop.parent
+	}
+	correlation := t.correlationConfig()
 	switch reportedSpan := span.(type) {
 	case *SegmentSpanImpl:
-		if len(value) > t.correlation.MaxValueSize {
+		if len(value) > correlation.MaxValueSize {
 			return
 		}
-		if len(reportedSpan.GetSegmentContext().CorrelationContext) >= t.correlation.MaxKeyCount {
+		if len(reportedSpan.GetSegmentContext().CorrelationContext) >= correlation.MaxKeyCount {
 			return
 		}
 		reportedSpan.Context().SetCorrelationContextValue(key, value)
 	case *RootSegmentSpan:
-		if len(value) > t.correlation.MaxValueSize {
+		if len(value) > correlation.MaxValueSize {
 			return
 		}
-		if len(reportedSpan.GetSegmentContext().CorrelationContext) >= t.correlation.MaxKeyCount {
+		if len(reportedSpan.GetSegmentContext().CorrelationContext) >= correlation.MaxKeyCount {
 			return
 		}
 		reportedSpan.Context().SetCorrelationContextValue(key, value)
@@ -225,11 +247,30 @@ func (s *ContextSnapshot) IsValid() bool {
 	return s.activeSpan != nil && s.runtime != nil
 }
 
+// InitKeepTracingWhenDisconnected configures whether keep creating the spans and propagating the context
+// when the backend is disconnected, the reported data is dropped or spooled by the reporter.
+// It's always enabled when the reporter spools the data, otherwise there is nothing to spool while disconnected.
+func (t *Tracer) InitKeepTracingWhenDisconnected(keepTracing bool) {
+	if spooler, ok := t.Reporter.(reporter.Spooler); ok && spooler.SpoolEnabled() {
+		keepTracing = true
+	}
+	t.keepTracingWhenDisconnected = keepTracing
+}
+
+// InitHonorUpstreamSampling configures whether the sampling decision of the upstream is authoritative,
+// the segment not sampled is recorded but not reported, and propagates the context with "not sampled" flag.
+func (t *Tracer) InitHonorUpstreamSampling(honor bool) {
+	t.honorUpstreamSampling = honor
+}
+
 func (t *Tracer) createNoop(operationName string) (*TracingContext, TracingSpan, bool) {
-	if !t.InitSuccess() || t.Reporter.ConnectionStatus() == reporter.ConnectionStatusDisconnect {
+	if !t.InitSuccess() {
 		return nil, newNoopSpan(), true
 	}
-	if tracerIgnore(operationName, t.ignoreSuffix, t.traceIgnorePath) {
+	if !t.keepTracingWhenDisconnected && t.Reporter.ConnectionStatus() == reporter.ConnectionStatusDisconnect {
+		return nil, newNoopSpan(), true
+	}
+	if tracerIgnore(operationName, loadStrings(&t.ignoreSuffix), loadStrings(&t.traceIgnorePath)) {
 		return nil, newNoopSpan(), true
 	}
 	ctx := getTracingContext()
@@ -255,24 +296,42 @@ func (t *Tracer) createSpan0(ctx *TracingContext, parent TracingSpan, pluginOpts
 			parentSpan = tmpSpan
 		}
 	}
+	// process the opts from agent core for prepare building segment span, the operation name and refs are used by sampling
+	for _, opt := range coreOpts {
+		opt.(tracing.SpanOption).Apply(ds)
+	}
 	isForceSample := len(ds.Refs) > 0
-	// Try to sample when it is not force sample
-	if parentSpan == nil && !isForceSample {
-		// Force sample
-		sampled := t.Sampler.IsSampled(ds.OperationName)
-		if !sampled {
-			// Filter by sample just return noop span
-			return newNoopSpan(), nil
+	headSampled := true
+	if parentSpan != nil {
+		// the spans in the segment or the continued segment follow the decision of the parent
+		headSampled = !parentSpan.GetSegmentContext().unsampled
+	} else if isForceSample {
+		// the upstream decision is authoritative when honor the upstream sampling
+		if ref, ok := ds.Refs[0].(*SpanContext); ok && t.honorUpstreamSampling {
+			headSampled = ref.Sample != 0
 		}
+	} else {
+		// Try to sample when it is not force sample
+		headSampled = t.Sampler.IsSampled(ds.OperationName)
 	}
-	// process the opts from agent core for prepare building segment span
-	for _, opt := range coreOpts {
-		opt.(tracing.SpanOption).Apply(ds)
+	// the tail sampling records every segment, and decides to report or not when the segment finished,
+	// and the segment not sampled still propagates the context when honor the upstream sampling
+	if !headSampled && t.tailSampling == nil && !t.honorUpstreamSampling {
+		// Filter by sample just return noop span
+		return newNoopSpan(), nil
+	}
+	// the segment is full, the noop span keeps the parent for propagating the context
+	if parentSpan != nil && t.reachSpanLimit(parentSpan) {
+		return newPropagatingNoopSpan(parentSpan), nil
 	}
 	s, err = NewSegmentSpan(ctx, ds, parentSpan)
 	if err != nil {
 		return nil, err
 	}
+	if root, ok := s.(*RootSegmentSpan); ok {
+		root.headSampled = headSampled
+		root.unsampled = !headSampled
+	}
 	// process the opts from plugin, split opts because the DefaultSpan not contains the tracing context information(AdaptSpan)
 	for _, opt := range pluginOpts {
 		opt.(tracing.SpanOption).Apply(s)
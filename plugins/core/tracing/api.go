@@ -214,6 +214,10 @@ func (n *NoopSpan) SpanID() int32 {
 	return -1
 }
 
+func (n *NoopSpan) IsEntry() bool {
+	return false
+}
+
 func (n *NoopSpan) SetOperationName(string) {
 }
 func (n *NoopSpan) SetPeer(string) {
@@ -226,8 +230,15 @@ func (n *NoopSpan) Tag(string, string) {
 }
 func (n *NoopSpan) Log(...string) {
 }
+func (n *NoopSpan) LogFields([]Field, ...LogOption) {
+}
 func (n *NoopSpan) Error(...string) {
 }
+func (n *NoopSpan) RecordError(error, ...ErrorOption) {
+}
+func (n *NoopSpan) AddRef(Extractor) error {
+	return nil
+}
 func (n *NoopSpan) End() {
 }
 func (n *NoopSpan) PrepareAsync() {
@@ -31,13 +31,17 @@ type AdaptSpan interface {
 	GetTraceID() string
 	GetSegmentID() string
 	GetSpanID() int32
+	IsEntry() bool
 	SetOperationName(string)
 	SetPeer(string)
 	SetSpanLayer(int32)
 	SetComponent(int32)
 	Tag(string, string)
 	Log(...string)
+	LogFields(timestamp interface{}, kv ...interface{})
 	Error(...string)
+	RecordError(err error, stack []byte)
+	AddRef(extractor interface{}) error
 	End()
 }
 
@@ -61,6 +65,10 @@ func (s *SpanWrapper) SpanID() int32 {
 	return s.Span.GetSpanID()
 }
 
+func (s *SpanWrapper) IsEntry() bool {
+	return s.Span.IsEntry()
+}
+
 func (s *SpanWrapper) Tag(k, v string) {
 	s.Span.Tag(k, v)
 }
@@ -81,6 +89,18 @@ func (s *SpanWrapper) Log(v ...string) {
 	s.Span.Log(v...)
 }
 
+func (s *SpanWrapper) LogFields(fields []Field, opts ...LogOption) {
+	options := &logOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	kv := make([]interface{}, 0, len(fields)*2)
+	for _, f := range fields {
+		kv = append(kv, f.Key, f.Value)
+	}
+	s.Span.LogFields(options.timestamp, kv...)
+}
+
 func (s *SpanWrapper) SetComponent(v int32) {
 	s.Span.SetComponent(v)
 }
@@ -89,6 +109,28 @@ func (s *SpanWrapper) Error(v ...string) {
 	s.Span.Error(v...)
 }
 
+func (s *SpanWrapper) RecordError(err error, opts ...ErrorOption) {
+	if err == nil {
+		return
+	}
+	options := &errorOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	var stack []byte
+	if options.withStack {
+		stack = DebugStack()
+	}
+	s.Span.RecordError(err, stack)
+}
+
+func (s *SpanWrapper) AddRef(extractor Extractor) error {
+	if extractor == nil {
+		return errParameter
+	}
+	return s.Span.AddRef(extractorWrapper(extractor))
+}
+
 func (s *SpanWrapper) End() {
 	s.Span.End()
 }
@@ -17,6 +17,104 @@
 
 package tracing
 
+import (
+	"github.com/apache/skywalking-go/plugins/core/operator"
+)
+
 type ContextSnapshot interface {
 	IsValid() bool
 }
+
+// Context is the context.Context which could carry the span,
+// only declares the method for reading, so the "context" package is not required.
+type Context interface {
+	Value(key interface{}) interface{}
+}
+
+// ContextWithSpan returns a copy of the ctx(context.Context) which carries the span,
+// the result could be converted to context.Context.
+// The span could be the parent of the spans created by the "WithContext" APIs in any goroutine,
+// such as the worker pools, which cannot propagate the span through the goroutine.
+func ContextWithSpan(ctx Context, span Span) Context {
+	if ctx == nil || span == nil {
+		return ctx
+	}
+	op := operator.GetOperator()
+	if op == nil {
+		return ctx
+	}
+	wrapper, ok := span.(*SpanWrapper)
+	if !ok {
+		return ctx
+	}
+	if result, ok := op.Tracing().(operator.TracingOperator).ContextWithSpan(ctx, wrapper.Span).(Context); ok {
+		return result
+	}
+	return ctx
+}
+
+// SpanFromContext returns the span carried by the ctx(context.Context), or nil if not exists.
+// If the span is carried from other goroutine, it can only get information but cannot be operated.
+func SpanFromContext(ctx Context) Span {
+	if ctx == nil {
+		return nil
+	}
+	op := operator.GetOperator()
+	if op == nil {
+		return nil
+	}
+	if span, ok := op.Tracing().(operator.TracingOperator).SpanFromContext(ctx).(AdaptSpan); ok {
+		return newSpanAdapter(span)
+	}
+	return nil
+}
+
+// CreateEntrySpanWithContext creates a new entry span, the span carried by the ctx is the parent span if exists.
+func CreateEntrySpanWithContext(ctx Context, operationName string, extractor Extractor, opts ...SpanOption) (s Span, err error) {
+	if operationName == "" || extractor == nil {
+		return nil, errParameter
+	}
+	op := operator.GetOperator()
+	if op == nil {
+		return &NoopSpan{}, nil
+	}
+	span, err := op.Tracing().(operator.TracingOperator).CreateEntrySpanWithContext(ctx, operationName,
+		extractorWrapper(extractor), copyOptsAsInterface(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return newSpanAdapter(span.(AdaptSpan)), nil
+}
+
+// CreateLocalSpanWithContext creates a new local span, the span carried by the ctx is the parent span if exists.
+func CreateLocalSpanWithContext(ctx Context, operationName string, opts ...SpanOption) (s Span, err error) {
+	if operationName == "" {
+		return nil, errParameter
+	}
+	op := operator.GetOperator()
+	if op == nil {
+		return &NoopSpan{}, nil
+	}
+	span, err := op.Tracing().(operator.TracingOperator).CreateLocalSpanWithContext(ctx, operationName, copyOptsAsInterface(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return newSpanAdapter(span.(AdaptSpan)), nil
+}
+
+// CreateExitSpanWithContext creates a new exit span, the span carried by the ctx is the parent span if exists.
+func CreateExitSpanWithContext(ctx Context, operationName, peer string, injector Injector, opts ...SpanOption) (s Span, err error) {
+	if operationName == "" || peer == "" || injector == nil {
+		return nil, errParameter
+	}
+	op := operator.GetOperator()
+	if op == nil {
+		return &NoopSpan{}, nil
+	}
+	span, err := op.Tracing().(operator.TracingOperator).CreateExitSpanWithContext(ctx, operationName, peer,
+		injectorWrapper(injector), copyOptsAsInterface(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return newSpanAdapter(span.(AdaptSpan)), nil
+}
@@ -0,0 +1,79 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tracing
+
+import (
+	"github.com/apache/skywalking-go/plugins/core/operator"
+)
+
+// ProcessedSpan is the finished span which could be changed by the SegmentProcessor before reporting.
+type ProcessedSpan interface {
+	// OperationName of the span
+	OperationName() string
+	// SetOperationName renames the span
+	SetOperationName(name string)
+	// Peer of the exit span
+	Peer() string
+	// SpanLayer of the span, such as SpanLayerDatabase
+	SpanLayer() int32
+	// ComponentID of the span
+	ComponentID() int32
+	// IsEntry returns true if the span is an entry span
+	IsEntry() bool
+	// IsExit returns true if the span is an exit span
+	IsExit() bool
+	// IsError returns true if the span is marked as error
+	IsError() bool
+	// GetTag returns the value of the tag, and whether the tag exists
+	GetTag(key string) (string, bool)
+	// SetTag adds or replaces the tag
+	SetTag(key, value string)
+	// RemoveTag removes the tag
+	RemoveTag(key string)
+	// Drop the span, the children of the span are linked to its parent.
+	// The whole segment is dropped when the first span of the segment is dropped.
+	// The exit span and the span linked to other segments are kept, as the other segments reference them.
+	Drop()
+}
+
+// SegmentProcessor processes the spans which are not dropped of the finished segment before reporting,
+// the whole segment is dropped when it returns false.
+type SegmentProcessor func(spans []ProcessedSpan) bool
+
+// AddSegmentProcessor appends the processor to the end of the segment processor chain,
+// the processors run after the rules of "agent.segment_processors", in the order of registration.
+// Every call appends a new processor, so please register it only once after the agent core is initialized,
+// such as in the interceptor with sync.Once.
+func AddSegmentProcessor(processor SegmentProcessor) {
+	if processor == nil {
+		return
+	}
+	op := operator.GetOperator()
+	if op == nil {
+		return
+	}
+	op.Tracing().(operator.TracingOperator).AddSegmentProcessor(func(spans []interface{}) bool {
+		processed := make([]ProcessedSpan, 0, len(spans))
+		for _, s := range spans {
+			if span, ok := s.(ProcessedSpan); ok {
+				processed = append(processed, span)
+			}
+		}
+		return processor(processed)
+	})
+}
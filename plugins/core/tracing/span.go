@@ -91,6 +91,73 @@ func WithTag(key Tag, value string) SpanOption {
 	})
 }
 
+// ErrorOption allows for functional options to adjust the error recorded by RecordError
+type ErrorOption func(*errorOptions)
+
+type errorOptions struct {
+	withStack bool
+}
+
+// WithErrorStack record the stack of the current goroutine with the error
+func WithErrorStack() ErrorOption {
+	return func(o *errorOptions) {
+		o.withStack = true
+	}
+}
+
+// Field is a typed key/value pair of the structured log
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// StringField build a string Field
+func StringField(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// IntField build an integer Field
+func IntField(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// FloatField build a float Field
+func FloatField(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// BoolField build a boolean Field
+func BoolField(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// ErrorField build a Field with the message of error
+func ErrorField(key string, err error) Field {
+	return Field{Key: key, Value: err}
+}
+
+// LogOption allows for functional options to adjust the log recorded by LogFields
+type LogOption func(*logOptions)
+
+type logOptions struct {
+	timestamp interface{}
+}
+
+// WithLogTime set the time of the log, the value should be time.Time,
+// the log uses the current time by default
+func WithLogTime(t interface{}) LogOption {
+	return func(o *logOptions) {
+		o.timestamp = t
+	}
+}
+
+// WithLogTimestamp set the time of the log as the milliseconds since epoch
+func WithLogTimestamp(millis int64) LogOption {
+	return func(o *logOptions) {
+		o.timestamp = millis
+	}
+}
+
 type spanOpImpl struct {
 	exe func(s AdaptSpan)
 }
@@ -122,6 +189,9 @@ type Span interface {
 	TraceSegmentID() string
 	// SpanID of span
 	SpanID() int32
+	// IsEntry returns true when the Span is an entry span of the current goroutine,
+	// it's reused when creating an entry span in it, such as consuming the messages of a batch
+	IsEntry() bool
 
 	// Tag set the Tag of the Span
 	Tag(string, string)
@@ -135,8 +205,16 @@ type Span interface {
 	SetComponent(int32)
 	// Log add log to the Span
 	Log(...string)
+	// LogFields add the typed fields as a log to the Span, the log time could be given by WithLogTime,
+	// such as recording the events measured earlier
+	LogFields([]Field, ...LogOption)
 	// Error add error log to the Span
 	Error(...string)
+	// RecordError add the error as a structured log to the Span, and mark the Span as error
+	RecordError(error, ...ErrorOption)
+	// AddRef links the Span to the upstream context extracted from the carrier,
+	// such as linking every consumed message of a batch
+	AddRef(Extractor) error
 	// End end the Span
 	End()
 }
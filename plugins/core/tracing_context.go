@@ -0,0 +1,119 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"context"
+)
+
+// spanContextKey is the key of the span carried by the context.Context
+type spanContextKey struct{}
+
+// contextSpan is the span carried by the context.Context, the snapshot is taken when the span putting into the context,
+// then the span could be the parent of the spans created in other goroutines.
+type contextSpan struct {
+	span     TracingSpan
+	snapshot TracingSpan
+}
+
+func (t *Tracer) ContextWithSpan(ctx, span interface{}) interface{} {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		return ctx
+	}
+	s, ok := span.(TracingSpan)
+	if !ok || s == nil {
+		return ctx
+	}
+	return context.WithValue(c, spanContextKey{}, &contextSpan{span: s, snapshot: newSnapshotSpan(s)})
+}
+
+func (t *Tracer) SpanFromContext(ctx interface{}) interface{} {
+	if s := spanFromContext(ctx); s != nil {
+		return s.span
+	}
+	return nil
+}
+
+func (t *Tracer) CreateEntrySpanWithContext(ctx interface{}, operationName string, extractor interface{},
+	opts ...interface{}) (s interface{}, err error) {
+	return t.createSpanWithContext(ctx, func() (interface{}, error) {
+		return t.CreateEntrySpan(operationName, extractor, opts...)
+	})
+}
+
+func (t *Tracer) CreateLocalSpanWithContext(ctx interface{}, operationName string, opts ...interface{}) (s interface{}, err error) {
+	return t.createSpanWithContext(ctx, func() (interface{}, error) {
+		return t.CreateLocalSpan(operationName, opts...)
+	})
+}
+
+func (t *Tracer) CreateExitSpanWithContext(ctx interface{}, operationName, peer string, injector interface{},
+	opts ...interface{}) (s interface{}, err error) {
+	return t.createSpanWithContext(ctx, func() (interface{}, error) {
+		return t.CreateExitSpan(operationName, peer, injector, opts...)
+	})
+}
+
+// createSpanWithContext uses the span in the context as the parent when it's not the active span of current goroutine,
+// the active span is restored after the created span ended, so the goroutine could be reused by others.
+func (t *Tracer) createSpanWithContext(ctx interface{}, create func() (interface{}, error)) (interface{}, error) {
+	parent := spanFromContext(ctx)
+	tracingContext := getTracingContext()
+	if parent == nil || parent.snapshot == nil || (tracingContext != nil && tracingContext.ActiveSpan() == parent.span) {
+		return create()
+	}
+	if tracingContext == nil {
+		tracingContext = NewTracingContext()
+		SetGLS(tracingContext)
+	}
+	previous := tracingContext.ActiveSpan()
+	tracingContext.SaveActiveSpan(parent.parentSnapshot())
+	s, err := create()
+	// the span is not active in the goroutine, restore the previous one directly
+	restorer, ok := s.(activeSpanRestorer)
+	if err != nil || !ok || interface{}(tracingContext.ActiveSpan()) != s {
+		tracingContext.SaveActiveSpan(previous)
+		return s, err
+	}
+	restorer.restoreActiveOnEnd(previous)
+	return s, nil
+}
+
+// parentSnapshot returns the snapshot as the parent, the noop snapshot counts the created spans,
+// so it cannot be shared between goroutines
+func (c *contextSpan) parentSnapshot() TracingSpan {
+	if _, isNoop := c.snapshot.(*NoopSpan); isNoop {
+		return newSnapshotNoopSpan()
+	}
+	return c.snapshot
+}
+
+func spanFromContext(ctx interface{}) *contextSpan {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		return nil
+	}
+	s, _ := c.Value(spanContextKey{}).(*contextSpan)
+	return s
+}
+
+// activeSpanRestorer restores the active span when the span ended, rather than changing to the parent span
+type activeSpanRestorer interface {
+	restoreActiveOnEnd(previous TracingSpan)
+}
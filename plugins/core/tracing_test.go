@@ -18,6 +18,9 @@
 package core
 
 import (
+	"bytes"
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -294,6 +297,45 @@ func TestReporterDisconnect(t *testing.T) {
 	assert.Equal(t, 0, len(spans), "should no span been collected")
 }
 
+func TestReporterDisconnectKeepTracing(t *testing.T) {
+	defer ResetTracingContext()
+	ReportConnectionStatus = reporter.ConnectionStatusDisconnect
+	Tracing.InitKeepTracingWhenDisconnected(true)
+	s, err := tracing.CreateEntrySpan("/entry", func(key string) (string, error) {
+		return "", nil
+	})
+	assert.NoError(t, err)
+	injectedHeader := ""
+	exit, err := tracing.CreateExitSpan("/exit", "localhost:8080", func(key, value string) error {
+		if key == Header {
+			injectedHeader = value
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", injectedHeader, "context should be propagated")
+	exit.End()
+	s.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 2, len(spans), "spans should be collected")
+}
+
+func TestReporterDisconnectSpooling(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.Reporter = &spoolingReporter{StoreReporter: NewStoreReporter()}
+	Tracing.InitKeepTracingWhenDisconnected(false)
+	assert.True(t, Tracing.keepTracingWhenDisconnected, "should keep tracing when the reporter spools the data")
+}
+
+type spoolingReporter struct {
+	*StoreReporter
+}
+
+func (r *spoolingReporter) SpoolEnabled() bool {
+	return true
+}
+
 func TestSpanOperation(t *testing.T) {
 	defer ResetTracingContext()
 	spanCreations := []func(op tracing.SpanOption) (tracing.Span, error){
@@ -455,6 +497,396 @@ func TestNoopSpan(t *testing.T) {
 	assert.Nil(t, activeSpan, "active span should be nil")
 }
 
+func TestSpanLimitPerSegment(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.InitSpanLimit(2)
+	entry, err := tracing.CreateEntrySpan("/entry", func(key string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	local, err := tracing.CreateLocalSpan("/local")
+	assert.NoError(t, err)
+
+	// the spans over the limit are noop, but the context is still propagated
+	overflow, err := tracing.CreateLocalSpan("/overflow")
+	assert.NoError(t, err)
+	assert.Equal(t, local.TraceID(), overflow.TraceID(), "trace id not correct")
+	injected := SpanContext{}
+	exit, err := tracing.CreateExitSpan("/exit", "localhost:8080", func(key, value string) error {
+		if key == Header {
+			assert.NoError(t, injected.DecodeSW8(value))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, local.TraceID(), injected.TraceID, "injected trace id not correct")
+	assert.Equal(t, local.SpanID(), injected.ParentSpanID, "injected parent span id not correct")
+	exit.End()
+	overflow.End()
+	assert.Equal(t, local, tracing.ActiveSpan(), "active span should be restored")
+	local.End()
+	entry.End()
+
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 2, len(spans), "span count not correct")
+	root := spans[len(spans)-1]
+	assert.Equal(t, "/entry", root.OperationName())
+	assert.Equal(t, spanLimitTruncatedTag, root.Tags()[0].Key, "segment should be tagged as truncated")
+}
+
+func TestSpanDataLimit(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.InitSpanDataLimit(&SpanDataLimitConfig{MaxTagCount: 2, MaxTagValueSize: 4, MaxLogCount: 1, MaxLogValueSize: 6})
+	s, err := tracing.CreateLocalSpan("/local")
+	assert.NoError(t, err)
+	s.Tag("key1", "value1")
+	s.Tag("key2", "测试")
+	s.Tag("key3", "value3")
+	s.Tag("key1", "v1")
+	s.Log("event", "first", "message", "first message")
+	s.Log("event", "second")
+	s.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 1, len(spans), "span count not correct")
+	tags := make(map[string]string)
+	for _, tag := range spans[0].Tags() {
+		tags[tag.Key] = tag.Value
+	}
+	assert.Equal(t, map[string]string{"key1": "v1", "key2": "测", spanDataTruncatedTag: "true"}, tags)
+	assert.Equal(t, 1, len(spans[0].Logs()), "logs over the limit should be dropped")
+	assert.Equal(t, "first ", spans[0].Logs()[0].Data[1].Value, "log value should be truncated")
+}
+
+func TestTailSampling(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.Sampler = NewConstSampler(false)
+	Tracing.InitTailSampling(true, 0, "GET:/slow/**:10")
+	for _, name := range []string{"GET:/fast", "GET:/error", "GET:/slow/1", "GET:/child-error"} {
+		s, err := tracing.CreateEntrySpan(name, func(key string) (string, error) { return "", nil })
+		assert.NoError(t, err)
+		assert.NotEmpty(t, s.TraceID(), "the span should be recorded")
+		switch name {
+		case "GET:/error":
+			s.Error("event", "error")
+		case "GET:/slow/1":
+			time.Sleep(time.Millisecond * 20)
+		case "GET:/child-error":
+			child, err := tracing.CreateLocalSpan("/child")
+			assert.NoError(t, err)
+			child.Error("event", "error")
+			child.End()
+		}
+		s.End()
+	}
+	time.Sleep(time.Millisecond * 50)
+	names := make([]string, 0)
+	for _, span := range GetReportedSpans() {
+		names = append(names, span.OperationName())
+	}
+	assert.ElementsMatch(t, []string{"GET:/error", "GET:/slow/1", "/child", "GET:/child-error"}, names)
+}
+
+func TestHonorUpstreamSampling(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.Sampler = NewConstSampler(false)
+	Tracing.InitHonorUpstreamSampling(true)
+	upstreamTraceIDs := map[int8]string{0: "not-sampled-trace", 1: "sampled-trace"}
+	for _, upstream := range []int8{-1, 0, 1} {
+		header := ""
+		if upstream >= 0 {
+			header = (&SpanContext{Sample: upstream, TraceID: upstreamTraceIDs[upstream], ParentSegmentID: "segment",
+				ParentService: "service", ParentServiceInstance: "instance", ParentEndpoint: "/upstream",
+				AddressUsedAtClient: "localhost"}).EncodeSW8()
+		}
+		entry, err := tracing.CreateEntrySpan("/entry", func(key string) (string, error) {
+			if key == Header {
+				return header, nil
+			}
+			return "", nil
+		})
+		assert.NoError(t, err)
+		injected := SpanContext{}
+		exit, err := tracing.CreateExitSpan("/exit", "localhost:8080", func(key, value string) error {
+			if key == Header {
+				assert.NoError(t, injected.DecodeSW8(value))
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		// the context is propagated even the segment is not sampled
+		assert.Equal(t, entry.TraceID(), injected.TraceID, "injected trace id not correct")
+		expectedSample := int8(0)
+		if upstream == 1 {
+			expectedSample = 1
+		}
+		assert.Equal(t, expectedSample, injected.Sample, "injected sample flag not correct")
+		exit.End()
+		entry.End()
+	}
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 2, len(spans), "only the segment sampled by upstream should be reported")
+	for _, span := range spans {
+		assert.Equal(t, "sampled-trace", span.Context().GetTraceID())
+	}
+}
+
+func TestTailSamplingPropagation(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.Sampler = NewConstSampler(false)
+	Tracing.InitHonorUpstreamSampling(true)
+	Tracing.InitTailSampling(true, 0, "")
+	entry, err := tracing.CreateEntrySpan("/entry", func(key string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	injected := SpanContext{}
+	exit, err := tracing.CreateExitSpan("/exit", "localhost:8080", func(key, value string) error {
+		if key == Header {
+			assert.NoError(t, injected.DecodeSW8(value))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	// the undecided segment should make the downstream keep recording
+	assert.Equal(t, int8(1), injected.Sample, "injected sample flag not correct")
+	exit.Error("event", "error")
+	exit.End()
+	entry.End()
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, 2, len(GetReportedSpans()), "the error segment should be kept by the tail sampling")
+}
+
+func TestSegmentProcessors(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.InitSegmentProcessors("operation=GET:/health/**=>drop_segment;type=local&operation=/drop=>drop_span;" +
+		"tag=db.statement=>redact=db.statement;layer=Database=>rename=db-op;operation=/keep=>drop_span")
+	tracing.AddSegmentProcessor(func(spans []tracing.ProcessedSpan) bool {
+		for _, s := range spans {
+			if s.OperationName() == "GET:/plugin-drop" {
+				return false
+			}
+			s.SetTag("processed", "true")
+		}
+		return true
+	})
+	for _, name := range []string{"GET:/health/ping", "GET:/plugin-drop", "GET:/api"} {
+		entry, err := tracing.CreateEntrySpan(name, func(key string) (string, error) { return "", nil })
+		assert.NoError(t, err)
+		if name == "GET:/api" {
+			local, err := tracing.CreateLocalSpan("/drop")
+			assert.NoError(t, err)
+			exit, err := tracing.CreateExitSpan("/db", "localhost:3306", func(key, value string) error { return nil },
+				tracing.WithLayer(tracing.SpanLayerDatabase), tracing.WithTag("db.statement", "select 1"))
+			assert.NoError(t, err)
+			exit.End()
+			local.End()
+			keep, err := tracing.CreateExitSpan("/keep", "localhost:8080", func(key, value string) error { return nil })
+			assert.NoError(t, err)
+			keep.End()
+		}
+		entry.End()
+	}
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 3, len(spans), "only the spans of GET:/api should be reported")
+	reported := make(map[string]reporter.ReportedSpan)
+	for _, span := range spans {
+		reported[span.OperationName()] = span
+		tags := make(map[string]string)
+		for _, tag := range span.Tags() {
+			tags[tag.Key] = tag.Value
+		}
+		assert.Equal(t, "true", tags["processed"], "the span should be processed by the plugin processor")
+		if span.OperationName() == "db-op" {
+			assert.Equal(t, "******", tags["db.statement"], "the statement should be redacted")
+		}
+	}
+	assert.NotNil(t, reported["GET:/api"])
+	assert.NotNil(t, reported["db-op"])
+	assert.NotNil(t, reported["/keep"], "the exit span should not be dropped")
+	assert.Equal(t, reported["GET:/api"].Context().GetSpanID(), reported["db-op"].Context().GetParentSpanID(),
+		"the child of the dropped span should be linked to the parent")
+}
+
+func TestSegmentProcessorsKeepCapturedSpan(t *testing.T) {
+	defer ResetTracingContext()
+	Tracing.InitSegmentProcessors("operation=/async=>drop_span")
+	entry, err := tracing.CreateEntrySpan("GET:/api", func(key string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	local, err := tracing.CreateLocalSpan("/async")
+	assert.NoError(t, err)
+	snapshot := tracing.CaptureContext()
+	oldGLS := GetGLS()
+
+	// the segment of the child goroutine references the captured span
+	SetAsNewGoroutine()
+	tracing.ContinueContext(snapshot)
+	child, err := tracing.CreateLocalSpan("/child")
+	assert.NoError(t, err)
+	child.End()
+
+	SetGLS(oldGLS)
+	local.End()
+	entry.End()
+	time.Sleep(time.Millisecond * 50)
+	reported := make(map[string]reporter.ReportedSpan)
+	for _, span := range GetReportedSpans() {
+		reported[span.OperationName()] = span
+	}
+	assert.Equal(t, 3, len(reported), "the captured span should not be dropped")
+	assert.NotNil(t, reported["/async"])
+	assert.Equal(t, reported["/async"].Context().GetSegmentID(), reported["/child"].Context().GetParentSegmentID())
+	assert.Equal(t, reported["/async"].Context().GetSpanID(), reported["/child"].Context().GetParentSpanID(),
+		"the child segment should reference the captured span")
+}
+
+func TestParseSegmentProcessRules(t *testing.T) {
+	rules, err := parseSegmentProcessRules("layer=Http&component=5&type=entry&tag=http.method:GET=>tag=k:v; =>remove_tag=k")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rules))
+	assert.Equal(t, 4, len(rules[0].conditions))
+	assert.Equal(t, 0, len(rules[1].conditions))
+	for _, invalid := range []string{"layer=Http", "layer=Other=>drop_span", "operation=/a=>unknown",
+		"component=a=>drop_span", "=>tag=k", "=>rename="} {
+		_, err = parseSegmentProcessRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+type testRecordedError struct {
+}
+
+func (e *testRecordedError) Error() string {
+	return "test error"
+}
+
+func TestRecordError(t *testing.T) {
+	defer ResetTracingContext()
+	s, err := tracing.CreateLocalSpan("/local")
+	assert.NoError(t, err)
+	s.RecordError(nil)
+	s.RecordError(&testRecordedError{}, tracing.WithErrorStack())
+	s.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 1, len(spans), "span count not correct")
+	assert.True(t, spans[0].IsError(), "span should be error")
+	assert.Equal(t, 1, len(spans[0].Logs()), "only the not nil error should be recorded")
+	data := make(map[string]string)
+	for _, kv := range spans[0].Logs()[0].Data {
+		data[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "error", data["event"])
+	assert.Equal(t, "*core.testRecordedError", data["error.kind"])
+	assert.Equal(t, "test error", data["message"])
+	assert.NotEmpty(t, data["stack"], "stack should be recorded")
+}
+
+func TestTruncateErrorStack(t *testing.T) {
+	stack := []byte("goroutine 1 [running]:\nmain.main()\n")
+	assert.Equal(t, string(stack), truncateErrorStack(stack))
+	long := bytes.Repeat([]byte("frame\n"), maxErrorStackSize)
+	truncated := truncateErrorStack(long)
+	assert.True(t, len(truncated) <= maxErrorStackSize+len(errorStackTruncatedSuffix), "stack should be limited")
+	assert.True(t, strings.HasSuffix(truncated, "frame"+errorStackTruncatedSuffix), "stack should be cut at the frame line")
+}
+
+func TestLogFields(t *testing.T) {
+	defer ResetTracingContext()
+	s, err := tracing.CreateLocalSpan("/local")
+	assert.NoError(t, err)
+	resolved := time.Now().Add(-time.Second)
+	s.LogFields([]tracing.Field{
+		tracing.StringField("event", "dns resolved"),
+		tracing.IntField("addresses", 2),
+		tracing.FloatField("cost", 1.5),
+		tracing.BoolField("cached", true),
+		tracing.ErrorField("error", &testRecordedError{}),
+	}, tracing.WithLogTime(resolved))
+	s.LogFields([]tracing.Field{tracing.StringField("event", "enqueued")}, tracing.WithLogTimestamp(1000))
+	s.LogFields([]tracing.Field{tracing.StringField("event", "now")})
+	s.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 1, len(spans), "span count not correct")
+	logs := spans[0].Logs()
+	assert.Equal(t, 3, len(logs), "log count not correct")
+	assert.Equal(t, Millisecond(resolved), logs[0].Time, "log time should be the given time")
+	data := make(map[string]string)
+	for _, kv := range logs[0].Data {
+		data[kv.Key] = kv.Value
+	}
+	assert.Equal(t, map[string]string{
+		"event": "dns resolved", "addresses": "2", "cost": "1.5", "cached": "true", "error": "test error",
+	}, data)
+	assert.Equal(t, int64(1000), logs[1].Time, "log time should be the given timestamp")
+	assert.True(t, logs[2].Time >= Millisecond(resolved), "log time should be the current time")
+	assert.False(t, spans[0].IsError(), "span should not be error")
+}
+
+func TestCreateSpanWithContext(t *testing.T) {
+	defer ResetTracingContext()
+	entry, err := tracing.CreateEntrySpan("/entry", func(key string) (string, error) { return "", nil })
+	assert.NoError(t, err)
+	ctx := tracing.ContextWithSpan(context.Background(), entry)
+	assert.Equal(t, entry.SpanID(), tracing.SpanFromContext(ctx).SpanID(), "span should be carried by context")
+	assert.Nil(t, tracing.SpanFromContext(context.Background()), "span should not be in the empty context")
+	oldGLS := GetGLS()
+
+	// switch to a reused goroutine which has no relation with the span
+	SetGLS(nil)
+	local, err := tracing.CreateLocalSpanWithContext(ctx, "/local")
+	assert.NoError(t, err)
+	assert.Equal(t, entry.TraceID(), local.TraceID(), "trace id not correct")
+	assert.Equal(t, entry.TraceSegmentID(), local.TraceSegmentID(), "segment id not correct")
+	assert.Equal(t, local.SpanID(), tracing.ActiveSpan().SpanID(), "created span should be active")
+	local.End()
+	assert.Nil(t, tracing.ActiveSpan(), "active span should be restored")
+
+	SetGLS(oldGLS)
+	entry.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 2, len(spans), "span count not correct")
+	assert.Equal(t, int32(0), spans[0].Context().GetParentSpanID(), "parent span id not correct")
+}
+
+func TestAddRef(t *testing.T) {
+	defer ResetTracingContext()
+	extractor := func(key string) (string, error) {
+		if key == Header {
+			return header, nil
+		}
+		return "", nil
+	}
+	s, err := tracing.CreateEntrySpan("/entry", extractor)
+	assert.NoError(t, err)
+	// the duplicate and empty context are ignored
+	assert.NoError(t, s.AddRef(extractor))
+	assert.NoError(t, s.AddRef(func(key string) (string, error) { return "", nil }))
+	other := SpanContext{
+		Sample:                sample,
+		TraceID:               "2f2d4bf47bf711eab794acde48001122",
+		ParentSegmentID:       "2e7c204a7bf711eab858acde48001122",
+		ParentService:         parentService,
+		ParentServiceInstance: parentServiceInstance,
+		ParentEndpoint:        parentEndpoint,
+		AddressUsedAtClient:   addressUsedAtClient,
+	}
+	otherHeader := other.EncodeSW8()
+	assert.NoError(t, s.AddRef(func(key string) (string, error) {
+		if key == Header {
+			return otherHeader, nil
+		}
+		return "", nil
+	}))
+	s.End()
+	time.Sleep(time.Millisecond * 50)
+	spans := GetReportedSpans()
+	assert.Equal(t, 1, len(spans), "span count not correct")
+	assert.Equal(t, 2, len(spans[0].Refs()), "refs not correct")
+	assert.Equal(t, other.TraceID, spans[0].Refs()[1].GetTraceID(), "linked trace id not correct")
+}
+
 func validateSpanOperation(t *testing.T, cases []spanOperationTestCase) {
 	for _, tt := range cases {
 		spans := make([]tracing.Span, 0)
@@ -45,7 +45,10 @@ func newRedisHook(addr string) *redisHook {
 
 func (r *redisHook) DialHook(next redis.DialHook) redis.DialHook {
 	return func(ctx context.Context, network, addr string) (net.Conn, error) {
-		s, err := tracing.CreateExitSpan(
+		s, err := tracing.CreateExitSpanWithContext(
+			// the span carried by the context is preferred as the parent
+			ctx,
+
 			// operationName
 			GoRedisCacheType+"/"+"dial",
 
@@ -80,7 +83,10 @@ func (r *redisHook) DialHook(next redis.DialHook) redis.DialHook {
 
 func (r *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
 	return func(ctx context.Context, cmd redis.Cmder) error {
-		s, err := tracing.CreateExitSpan(
+		s, err := tracing.CreateExitSpanWithContext(
+			// the span carried by the context is preferred as the parent
+			ctx,
+
 			// operationName
 			GoRedisCacheType+"/"+cmd.FullName(),
 
@@ -132,7 +138,10 @@ func (r *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.Pr
 			summary += "..."
 		}
 
-		s, err := tracing.CreateExitSpan(
+		s, err := tracing.CreateExitSpanWithContext(
+			// the span carried by the context is preferred as the parent
+			ctx,
+
 			// operationName
 			"redis/pipeline",
 
@@ -168,7 +177,7 @@ func (r *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.Pr
 
 func recordError(span tracing.Span, err error) {
 	if err != redis.Nil {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 }
 
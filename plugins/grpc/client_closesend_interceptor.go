@@ -18,8 +18,6 @@
 package grpc
 
 import (
-	"strings"
-
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/tracing"
 )
@@ -30,7 +28,7 @@ type ClientCloseSendInterceptor struct {
 func (h *ClientCloseSendInterceptor) BeforeInvoke(invocation operator.Invocation) error {
 	cs := invocation.CallerInstance().(*nativeclientStream)
 	method := cs.callHdr.Method
-	if strings.HasPrefix(method, skywalkingService) {
+	if isReporterMethod(method) {
 		return nil
 	}
 	s, err := tracing.CreateLocalSpan(formatOperationName(method, "/Client/Response/CloseSend"),
@@ -51,7 +49,7 @@ func (h *ClientCloseSendInterceptor) AfterInvoke(invocation operator.Invocation,
 	}
 	span := invocation.GetContext().(tracing.Span)
 	if err, ok := result[0].(error); ok && err != nil {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	span.End()
 	return nil
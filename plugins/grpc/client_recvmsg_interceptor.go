@@ -19,7 +19,6 @@ package grpc
 
 import (
 	"io"
-	"strings"
 
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/tracing"
@@ -31,7 +30,7 @@ type ClientRecvMsgInterceptor struct {
 func (h *ClientRecvMsgInterceptor) BeforeInvoke(invocation operator.Invocation) error {
 	cs := invocation.CallerInstance().(*nativeclientStream)
 	method := cs.callHdr.Method
-	if strings.HasPrefix(method, skywalkingService) {
+	if isReporterMethod(method) {
 		return nil
 	}
 	csEnhanced, ok := invocation.CallerInstance().(operator.EnhancedInstance)
@@ -59,7 +58,7 @@ func (h *ClientRecvMsgInterceptor) AfterInvoke(invocation operator.Invocation, r
 	span := invocation.GetContext().(tracing.Span)
 	err, ok := result[0].(error)
 	if ok && err != nil && err != io.EOF {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	if err == io.EOF {
 		cs := invocation.CallerInstance().(*nativeclientStream)
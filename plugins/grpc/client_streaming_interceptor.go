@@ -19,7 +19,6 @@ package grpc
 
 import (
 	"context"
-	"strings"
 
 	"google.golang.org/grpc/metadata"
 
@@ -48,10 +47,10 @@ func (h *ClientStreamingInterceptor) BeforeInvoke(invocation operator.Invocation
 	clientconn := invocation.CallerInstance().(*nativeClientConn)
 	ctx := invocation.Args()[0].(context.Context)
 	remoteAddr := clientconn.Target()
-	if strings.HasPrefix(method, skywalkingService) {
+	if isReporterMethod(method) {
 		return nil
 	}
-	s, err := tracing.CreateExitSpan(formatOperationName(method, ""), remoteAddr, func(headerKey, headerValue string) error {
+	s, err := tracing.CreateExitSpanWithContext(ctx, formatOperationName(method, ""), remoteAddr, func(headerKey, headerValue string) error {
 		ctx = metadata.AppendToOutgoingContext(ctx, headerKey, headerValue)
 		invocation.ChangeArg(0, ctx)
 		return nil
@@ -74,7 +73,7 @@ func (h *ClientStreamingInterceptor) AfterInvoke(invocation operator.Invocation,
 	}
 	span := invocation.GetContext().(tracing.Span)
 	if err, ok := result[0].(error); ok && err != nil {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	span.PrepareAsync()
 	continueSnapShot := tracing.CaptureContext()
@@ -19,7 +19,6 @@ package grpc
 
 import (
 	"context"
-	"strings"
 
 	"google.golang.org/grpc/metadata"
 
@@ -35,10 +34,10 @@ func (h *ClientUnaryInterceptor) BeforeInvoke(invocation operator.Invocation) er
 	method := invocation.Args()[1].(string)
 	clientconn := invocation.CallerInstance().(*nativeClientConn)
 	remoteAddr := clientconn.Target()
-	if strings.HasPrefix(method, skywalkingService) {
+	if isReporterMethod(method) {
 		return nil
 	}
-	s, err := tracing.CreateExitSpan(formatOperationName(method, ""), remoteAddr, func(headerKey, headerValue string) error {
+	s, err := tracing.CreateExitSpanWithContext(ctx, formatOperationName(method, ""), remoteAddr, func(headerKey, headerValue string) error {
 		ctx = metadata.AppendToOutgoingContext(ctx, headerKey, headerValue)
 		invocation.ChangeArg(0, ctx)
 		return nil
@@ -61,7 +60,7 @@ func (h *ClientUnaryInterceptor) AfterInvoke(invocation operator.Invocation, res
 	}
 	span := invocation.GetContext().(tracing.Span)
 	if err, ok := result[0].(error); ok && err != nil {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	span.End()
 	return nil
@@ -17,6 +17,16 @@
 
 package grpc
 
+import "strings"
+
 var RPCTypeTag = "rpc.type"
 
 var skywalkingService = "/skywalking"
+
+// otlpCollectorService is the prefix of OpenTelemetry collector services used by the OTLP reporter
+var otlpCollectorService = "/opentelemetry.proto.collector."
+
+// isReporterMethod checks the method is called by the reporter of agent, these calls should not be traced
+func isReporterMethod(method string) bool {
+	return strings.HasPrefix(method, skywalkingService) || strings.HasPrefix(method, otlpCollectorService)
+}
@@ -53,7 +53,7 @@ func (h *ServerRecvMsgInterceptor) AfterInvoke(invocation operator.Invocation, r
 	span := invocation.GetContext().(tracing.Span)
 	err, ok := result[0].(error)
 	if ok && err != nil && err != io.EOF {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	if err == io.EOF {
 		ss := invocation.CallerInstance().(*nativeserverStream)
@@ -51,7 +51,7 @@ func (h *ServerSendMsgInterceptor) AfterInvoke(invocation operator.Invocation, r
 	}
 	span := invocation.GetContext().(tracing.Span)
 	if err, ok := result[0].(error); ok && err != nil {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	span.End()
 	return nil
@@ -49,7 +49,7 @@ func (h *ServerSendResponseInterceptor) AfterInvoke(invocation operator.Invocati
 	}
 	span := invocation.GetContext().(tracing.Span)
 	if err, ok := result[0].(error); ok && err != nil {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	span.End()
 	return nil
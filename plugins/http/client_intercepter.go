@@ -25,11 +25,17 @@ import (
 	"github.com/apache/skywalking-go/plugins/core/tracing"
 )
 
+// skywalkingReporterHeader is set by the HTTP reporter of the agent, these requests should not be traced
+const skywalkingReporterHeader = "Sw-Agent-Reporter"
+
 type ClientInterceptor struct {
 }
 
 func (h *ClientInterceptor) BeforeInvoke(invocation operator.Invocation) error {
 	request := invocation.Args()[0].(*http.Request)
+	if request.Header.Get(skywalkingReporterHeader) != "" {
+		return nil
+	}
 	s, err := tracing.CreateExitSpan(fmt.Sprintf("%s:%s", request.Method, request.URL.Path), request.Host, func(headerKey, headerValue string) error {
 		request.Header.Add(headerKey, headerValue)
 		return nil
@@ -53,7 +59,7 @@ func (h *ClientInterceptor) AfterInvoke(invocation operator.Invocation, result .
 		span.Tag(tracing.TagStatusCode, fmt.Sprintf("%d", resp.StatusCode))
 	}
 	if err, ok := result[1].(error); ok && err != nil {
-		span.Error(err.Error())
+		span.RecordError(err)
 	}
 	span.End()
 	return nil
@@ -57,3 +57,21 @@ func TestClientInvoke(t *testing.T) {
 	assert.Nil(t, spans[0].Refs(), "refs should be nil")
 	assert.Greater(t, spans[0].EndTime(), spans[0].StartTime(), "end time should be greater than start time")
 }
+
+func TestClientInvokeIgnoreReporter(t *testing.T) {
+	defer core.ResetTracingContext()
+	interceptor := &ClientInterceptor{}
+	request, err := http.NewRequest("POST", "http://localhost/v3/segments", http.NoBody)
+	assert.Nil(t, err, "new request error should be nil")
+	request.Header.Set(skywalkingReporterHeader, "true")
+	invocation := operator.NewInvocation(nil, request)
+	err = interceptor.BeforeInvoke(invocation)
+	assert.Nil(t, err, "before invoke error should be nil")
+	assert.Nil(t, invocation.GetContext(), "context should be nil")
+
+	err = interceptor.AfterInvoke(invocation, &http.Response{
+		StatusCode: 200,
+	}, nil)
+	assert.Nil(t, err, "after invoke error should be nil")
+	assert.Nil(t, core.GetReportedSpans(), "spans should be nil")
+}
@@ -17,7 +17,9 @@
 
 package http
 
+import "github.com/apache/skywalking-go/plugins/core/tools"
+
 //skywalking:config http
 var config struct {
-	ServerCollectParameters bool `config:"server_collect_parameters"`
+	ServerCollectParameters *tools.DynamicValue `config:"server_collect_parameters" dynamic:"bool"`
 }
@@ -61,6 +61,13 @@ func (i *Instrument) Points() []*instrument.Point {
 				instrument.WithArgType(1, "*Request")),
 			Interceptor: "ServerInterceptor",
 		},
+		{
+			PackagePath: "",
+			At: instrument.NewMethodEnhance("*ServeMux", "findHandler",
+				instrument.WithArgsCount(1), instrument.WithArgType(0, "*Request"),
+				instrument.WithResultCount(4), instrument.WithResultType(1, "string")),
+			Interceptor: "RouteInterceptor",
+		},
 	}
 }
 
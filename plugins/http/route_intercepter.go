@@ -0,0 +1,77 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+)
+
+// RouteInterceptor records the matched pattern of ServeMux(since Go 1.22) as the entry span name,
+// such as "GET /items/{id}" to "GET:/items/{id}". Only the outermost matched pattern is used,
+// the nested ServeMux(such as wrapped by http.StripPrefix) would not change it
+type RouteInterceptor struct {
+}
+
+func (h *RouteInterceptor) BeforeInvoke(invocation operator.Invocation) error {
+	return nil
+}
+
+func (h *RouteInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
+	if len(result) < 3 || !patternMatched(result[2]) {
+		return nil
+	}
+	pattern, ok := result[1].(string)
+	request, _ := invocation.Args()[0].(*http.Request)
+	if !ok || request == nil {
+		return nil
+	}
+	route, _ := tracing.GetRuntimeContextValue(serverRouteKey).(*serverRoute)
+	if route == nil || route.operationName != "" {
+		return nil
+	}
+	path := patternPath(pattern)
+	if path == "" {
+		return nil
+	}
+	route.operationName = request.Method + ":" + path
+	return nil
+}
+
+// patternMatched checks the *pattern returned by ServeMux.findHandler is not nil,
+// it's nil for the not found handler, and the redirect handler which returns the redirected path instead of a pattern
+func patternMatched(pattern interface{}) bool {
+	value := reflect.ValueOf(pattern)
+	return value.Kind() == reflect.Ptr && !value.IsNil()
+}
+
+// patternPath returns the path of the pattern formatted as "[METHOD ][HOST]/[PATH]", it's empty when the pattern has no path
+func patternPath(pattern string) string {
+	if index := strings.IndexByte(pattern, ' '); index >= 0 {
+		pattern = strings.TrimLeft(pattern[index+1:], " \t")
+	}
+	index := strings.IndexByte(pattern, '/')
+	if index < 0 {
+		return ""
+	}
+	return pattern[index:]
+}
@@ -0,0 +1,133 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/apache/skywalking-go/plugins/core"
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPattern is the placeholder of the *pattern matched by ServeMux
+type testPattern struct{}
+
+func TestRouteInvoke(t *testing.T) {
+	defer core.ResetTracingContext()
+	serverInterceptor := &ServerInterceptor{}
+	routeInterceptor := &RouteInterceptor{}
+	request, err := http.NewRequest("GET", "http://localhost/items/123", http.NoBody)
+	assert.Nil(t, err, "new request error should be nil")
+	serverInvocation := operator.NewInvocation(nil, &testResponseWriter{}, request)
+	err = serverInterceptor.BeforeInvoke(serverInvocation)
+	assert.Nil(t, err, "before invoke error should be nil")
+
+	routeInvocation := operator.NewInvocation(nil, request)
+	err = routeInterceptor.AfterInvoke(routeInvocation, nil, "GET example.com/items/{id}", &testPattern{}, nil)
+	assert.Nil(t, err, "route after invoke error should be nil")
+
+	err = serverInterceptor.AfterInvoke(serverInvocation)
+	assert.Nil(t, err, "after invoke error should be nil")
+
+	spans := core.WaitReportedSpans(1)
+	assert.Equal(t, 1, len(spans), "spans length should be 1")
+	assert.Equal(t, "GET:/items/{id}", spans[0].OperationName(), "operation name should be the matched pattern")
+	assert.Nil(t, tracing.GetRuntimeContextValue(serverRouteKey), "route should be cleaned")
+}
+
+func TestNestedRouteInvoke(t *testing.T) {
+	defer core.ResetTracingContext()
+	serverInterceptor := &ServerInterceptor{}
+	routeInterceptor := &RouteInterceptor{}
+	request, err := http.NewRequest("GET", "http://localhost/api/items/123", http.NoBody)
+	assert.Nil(t, err, "new request error should be nil")
+	outerInvocation := operator.NewInvocation(nil, &testResponseWriter{}, request)
+	err = serverInterceptor.BeforeInvoke(outerInvocation)
+	assert.Nil(t, err, "outer before invoke error should be nil")
+	err = routeInterceptor.AfterInvoke(operator.NewInvocation(nil, request), nil, "/api/", &testPattern{}, nil)
+	assert.Nil(t, err, "outer route after invoke error should be nil")
+
+	// the inner ServeMux is wrapped by http.StripPrefix("/api", ...)
+	stripped, err := http.NewRequest("GET", "http://localhost/items/123", http.NoBody)
+	assert.Nil(t, err, "new request error should be nil")
+	innerInvocation := operator.NewInvocation(nil, &testResponseWriter{}, stripped)
+	err = serverInterceptor.BeforeInvoke(innerInvocation)
+	assert.Nil(t, err, "inner before invoke error should be nil")
+	err = routeInterceptor.AfterInvoke(operator.NewInvocation(nil, stripped), nil, "GET /items/{id}", &testPattern{}, nil)
+	assert.Nil(t, err, "inner route after invoke error should be nil")
+	err = serverInterceptor.AfterInvoke(innerInvocation)
+	assert.Nil(t, err, "inner after invoke error should be nil")
+
+	err = serverInterceptor.AfterInvoke(outerInvocation)
+	assert.Nil(t, err, "outer after invoke error should be nil")
+
+	spans := core.WaitReportedSpans(1)
+	assert.Equal(t, 1, len(spans), "spans length should be 1")
+	assert.Equal(t, "GET:/api/", spans[0].OperationName(), "operation name should be the outermost matched pattern")
+}
+
+func TestRouteInvokeWithoutServer(t *testing.T) {
+	defer core.ResetTracingContext()
+	request, err := http.NewRequest("GET", "http://localhost/items/123", http.NoBody)
+	assert.Nil(t, err, "new request error should be nil")
+	err = (&RouteInterceptor{}).AfterInvoke(operator.NewInvocation(nil, request), nil, "GET /items/{id}", &testPattern{}, nil)
+	assert.Nil(t, err, "route after invoke error should be nil")
+	assert.Nil(t, tracing.GetRuntimeContextValue(serverRouteKey), "route should not be recorded")
+}
+
+func TestRedirectRouteInvoke(t *testing.T) {
+	defer core.ResetTracingContext()
+	serverInterceptor := &ServerInterceptor{}
+	for _, method := range []string{"GET", "CONNECT"} {
+		request, err := http.NewRequest(method, "http://localhost/items", http.NoBody)
+		assert.Nil(t, err, "new request error should be nil")
+		serverInvocation := operator.NewInvocation(nil, &testResponseWriter{}, request)
+		err = serverInterceptor.BeforeInvoke(serverInvocation)
+		assert.Nil(t, err, "before invoke error should be nil")
+
+		// the redirect handler returns the redirected path without the pattern
+		err = (&RouteInterceptor{}).AfterInvoke(operator.NewInvocation(nil, request), nil, "/items/", (*testPattern)(nil), nil)
+		assert.Nil(t, err, "route after invoke error should be nil")
+		err = serverInterceptor.AfterInvoke(serverInvocation)
+		assert.Nil(t, err, "after invoke error should be nil")
+	}
+
+	spans := core.WaitReportedSpans(2)
+	assert.Equal(t, 2, len(spans), "spans length should be 2")
+	for _, span := range spans {
+		assert.NotContains(t, span.OperationName(), "/items/", "the redirected path should not be the operation name")
+	}
+}
+
+func TestPatternPath(t *testing.T) {
+	tests := map[string]string{
+		"":                      "",
+		"/":                     "/",
+		"/items/{id}":           "/items/{id}",
+		"GET /items/{id}":       "/items/{id}",
+		"POST  example.com/a/b": "/a/b",
+		"example.com/{$}":       "/{$}",
+	}
+	for pattern, expected := range tests {
+		assert.Equal(t, expected, patternPath(pattern), pattern)
+	}
+}
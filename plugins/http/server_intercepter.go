@@ -27,9 +27,23 @@ import (
 	"github.com/apache/skywalking-go/plugins/core/tracing"
 )
 
+// serverRouteKey is the runtime context key of the route shared by the nested ServeMux in the same request
+var serverRouteKey = "httpServerRoute"
+
 type ServerInterceptor struct {
 }
 
+// serverRoute is the matched route of the outermost ServeMux, which is used as the entry span name
+type serverRoute struct {
+	operationName string
+}
+
+type serverContext struct {
+	span tracing.Span
+	// route is nil in the nested ServeMux, such as wrapped by http.StripPrefix
+	route *serverRoute
+}
+
 func (h *ServerInterceptor) BeforeInvoke(invocation operator.Invocation) error {
 	request := invocation.Args()[1].(*http.Request)
 	s, err := tracing.CreateEntrySpan(fmt.Sprintf("%s:%s", request.Method, request.URL.Path), func(headerKey string) (string, error) {
@@ -42,21 +56,33 @@ func (h *ServerInterceptor) BeforeInvoke(invocation operator.Invocation) error {
 		return err
 	}
 
-	if config.ServerCollectParameters && request.URL != nil {
+	if config.ServerCollectParameters.Bool() && request.URL != nil {
 		s.Tag(tracing.TagHTTPParams, request.URL.RawQuery)
 	}
 
 	writer := invocation.Args()[0].(http.ResponseWriter)
 	invocation.ChangeArg(0, &writerWrapper{ResponseWriter: writer, statusCode: http.StatusOK})
-	invocation.SetContext(s)
+	ctx := &serverContext{span: s}
+	if _, nested := tracing.GetRuntimeContextValue(serverRouteKey).(*serverRoute); !nested {
+		ctx.route = &serverRoute{}
+		tracing.SetRuntimeContextValue(serverRouteKey, ctx.route)
+	}
+	invocation.SetContext(ctx)
 	return nil
 }
 
 func (h *ServerInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
-	if invocation.GetContext() == nil {
+	ctx, ok := invocation.GetContext().(*serverContext)
+	if !ok {
 		return nil
 	}
-	span := invocation.GetContext().(tracing.Span)
+	span := ctx.span
+	if ctx.route != nil {
+		tracing.SetRuntimeContextValue(serverRouteKey, nil)
+		if ctx.route.operationName != "" {
+			span.SetOperationName(ctx.route.operationName)
+		}
+	}
 	if wrapped, ok := invocation.Args()[0].(*writerWrapper); ok {
 		span.Tag(tracing.TagStatusCode, fmt.Sprintf("%d", wrapped.statusCode))
 	}
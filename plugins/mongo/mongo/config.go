@@ -17,7 +17,9 @@
 
 package mongo
 
+import "github.com/apache/skywalking-go/plugins/core/tools"
+
 //skywalking:config
 var config struct {
-	CollectStatement bool `config:"collect_statement"`
+	CollectStatement *tools.DynamicValue `config:"collect_statement" dynamic:"bool"`
 }
@@ -75,7 +75,7 @@ func (m *NewClientInterceptor) BeforeInvoke(invocation operator.Invocation) erro
 					return
 				}
 
-				if config.CollectStatement {
+				if config.CollectStatement.Bool() {
 					span.Tag(tracing.TagDBStatement, m.gettingStatements(startedEvent))
 				}
 
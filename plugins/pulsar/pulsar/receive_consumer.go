@@ -20,6 +20,7 @@ package pulsar
 import (
 	"github.com/apache/pulsar-client-go/pulsar"
 
+	"github.com/apache/skywalking-go/plugins/core/log"
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/tracing"
 )
@@ -48,9 +49,13 @@ func (r *ReceiveInterceptor) AfterInvoke(invocation operator.Invocation, result
 	peer := lookup.PhysicalAddr.String()
 	operationName := pulsarReceivePrefix + topic + pulsarReceiveSuffix
 
-	span, err := tracing.CreateEntrySpan(operationName, func(headerKey string) (string, error) {
+	extractor := func(headerKey string) (string, error) {
 		return message.Properties()[headerKey], nil
-	},
+	}
+	// the active entry span is reused when consuming the messages in a batch
+	active := tracing.ActiveSpan()
+	reused := active != nil && active.IsEntry()
+	span, err := tracing.CreateEntrySpan(operationName, extractor,
 		tracing.WithLayer(tracing.SpanLayerMQ),
 		tracing.WithComponent(pulsarReceiveComponentID),
 		tracing.WithTag(tracing.TagMQBroker, lookup.PhysicalAddr.String()),
@@ -59,12 +64,21 @@ func (r *ReceiveInterceptor) AfterInvoke(invocation operator.Invocation, result
 	if err != nil {
 		return err
 	}
+	// the extractor is ignored when reusing, so link the consumed message to the batch span
+	if reused {
+		if err = span.AddRef(extractor); err != nil {
+			log.Warnf("cannot link the message to the consumer span: %v", err)
+		}
+	}
 
 	if err, ok := result[1].(pulsar.Error); ok {
 		span.Tag(tracing.TagMQStatus, err.Error())
 		span.Error(err.Error())
 	}
 	span.SetPeer(peer)
-	span.End()
+	// the reused span is ended by the creator after the batch consumed
+	if !reused {
+		span.End()
+	}
 	return nil
 }
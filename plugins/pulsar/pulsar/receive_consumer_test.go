@@ -0,0 +1,90 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+
+	"github.com/apache/skywalking-go/plugins/core"
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiveInvoke(t *testing.T) {
+	defer core.ResetTracingContext()
+	err := (&ReceiveInterceptor{}).AfterInvoke(operator.NewInvocation(newTestConsumer()), newTestMessage(core.NewTestHeader("trace-1")), nil)
+	assert.Nil(t, err, "after invoke error should be nil")
+
+	spans := core.WaitReportedSpans(1)
+	assert.Equal(t, 1, len(spans), "spans length should be 1")
+	assert.Equal(t, "Pulsar/test/Consumer", spans[0].OperationName(), "operation name should be the topic")
+	assert.Equal(t, 1, len(spans[0].Refs()), "the message should be linked")
+	assert.Equal(t, "trace-1", spans[0].Refs()[0].GetTraceID(), "trace id of the ref not correct")
+}
+
+func TestReceiveInvokeInBatch(t *testing.T) {
+	defer core.ResetTracingContext()
+	batch, err := tracing.CreateEntrySpan("batch", func(headerKey string) (string, error) {
+		return "", nil
+	})
+	assert.Nil(t, err, "create batch span error should be nil")
+	consumer := newTestConsumer()
+	// the message with the invalid context should be skipped
+	for _, header := range []string{core.NewTestHeader("trace-1"), "invalid", core.NewTestHeader("trace-2")} {
+		err = (&ReceiveInterceptor{}).AfterInvoke(operator.NewInvocation(consumer), newTestMessage(header), nil)
+		assert.Nil(t, err, "after invoke error should be nil")
+	}
+	batch.End()
+
+	spans := core.WaitReportedSpans(1)
+	assert.Equal(t, 1, len(spans), "spans length should be 1")
+	assert.Equal(t, 2, len(spans[0].Refs()), "every valid message should be linked to the batch span")
+	assert.Equal(t, "trace-1", spans[0].Refs()[0].GetTraceID(), "trace id of the first ref not correct")
+	assert.Equal(t, "trace-2", spans[0].Refs()[1].GetTraceID(), "trace id of the second ref not correct")
+}
+
+type testLookupService struct {
+}
+
+func (l *testLookupService) Lookup(topic string) (*LookupResult, error) {
+	addr := &url.URL{Scheme: "pulsar", Host: "localhost:6650"}
+	return &LookupResult{LogicalAddr: addr, PhysicalAddr: addr}, nil
+}
+
+type testMessage struct {
+	pulsar.Message
+	properties map[string]string
+}
+
+func (m *testMessage) Properties() map[string]string {
+	return m.properties
+}
+
+func newTestConsumer() *nativeconsumer {
+	return &nativeconsumer{client: &nativeclient{lookupService: &testLookupService{}}, topic: "test",
+		options: &nativeConsumerOptions{Topic: "test"}}
+}
+
+func newTestMessage(header string) pulsar.Message {
+	return &testMessage{properties: map[string]string{core.Header: header}}
+}
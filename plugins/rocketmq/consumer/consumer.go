@@ -23,6 +23,7 @@ import (
 	"github.com/apache/rocketmq-client-go/v2/consumer"
 	"github.com/apache/rocketmq-client-go/v2/primitive"
 
+	"github.com/apache/skywalking-go/plugins/core/log"
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/tracing"
 )
@@ -49,22 +50,24 @@ func (c *SwConsumerInterceptor) BeforeInvoke(invocation operator.Invocation) err
 	topic, addr := subMsgs[0].Topic, subMsgs[0].StoreHost
 	operationName := rmqConsumerPrefix + topic + rmqConsumerSuffix
 
-	var (
-		span tracing.Span
-		err  error
+	span, err := tracing.CreateEntrySpan(operationName, func(headerKey string) (string, error) {
+		return subMsgs[0].GetProperty(headerKey), nil
+	},
+		tracing.WithLayer(tracing.SpanLayerMQ),
+		tracing.WithComponent(rmqConsumerComponentID),
+		tracing.WithTag(tracing.TagMQTopic, topic),
+		tracing.WithTag(tagMQMsgID, subMsgs[0].MsgId),
+		tracing.WithTag(tagMQOffsetMsgID, subMsgs[0].OffsetMsgId),
 	)
-	for _, msg := range subMsgs {
-		span, err = tracing.CreateEntrySpan(operationName, func(headerKey string) (string, error) {
+	if err != nil {
+		return err
+	}
+	// link the other messages in the batch to the span, the message with invalid context is skipped
+	for _, msg := range subMsgs[1:] {
+		if err = span.AddRef(func(headerKey string) (string, error) {
 			return msg.GetProperty(headerKey), nil
-		},
-			tracing.WithLayer(tracing.SpanLayerMQ),
-			tracing.WithComponent(rmqConsumerComponentID),
-			tracing.WithTag(tracing.TagMQTopic, topic),
-			tracing.WithTag(tagMQMsgID, msg.MsgId),
-			tracing.WithTag(tagMQOffsetMsgID, msg.OffsetMsgId),
-		)
-		if err != nil {
-			return err
+		}); err != nil {
+			log.Warnf("cannot link the message %s to the consumer span: %v", msg.MsgId, err)
 		}
 	}
 	span.Tag(tracing.TagMQBroker, addr)
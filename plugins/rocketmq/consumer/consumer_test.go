@@ -0,0 +1,78 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package consumer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/rocketmq-client-go/v2/consumer"
+	"github.com/apache/rocketmq-client-go/v2/primitive"
+
+	"github.com/apache/skywalking-go/plugins/core"
+	"github.com/apache/skywalking-go/plugins/core/operator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumerInvokeInBatch(t *testing.T) {
+	defer core.ResetTracingContext()
+	// the message with the invalid context should be skipped
+	msgs := []*primitive.MessageExt{newTestMessage(core.NewTestHeader("trace-1")), newTestMessage("invalid"),
+		newTestMessage(core.NewTestHeader("trace-2"))}
+	invocation := operator.NewInvocation(newTestConsumer(), context.Background(), msgs)
+	interceptor := &SwConsumerInterceptor{}
+	assert.Nil(t, interceptor.BeforeInvoke(invocation), "before invoke error should be nil")
+	assert.NotNil(t, invocation.GetContext(), "the span should be saved for ending")
+	assert.Nil(t, interceptor.AfterInvoke(invocation, consumer.ConsumeSuccess, nil), "after invoke error should be nil")
+
+	spans := core.WaitReportedSpans(1)
+	assert.Equal(t, 1, len(spans), "spans length should be 1")
+	assert.Equal(t, "RocketMQ/test/Consumer", spans[0].OperationName(), "operation name should be the topic")
+	assert.Equal(t, 2, len(spans[0].Refs()), "every valid message should be linked to the span")
+	assert.Equal(t, "trace-1", spans[0].Refs()[0].GetTraceID(), "trace id of the first ref not correct")
+	assert.Equal(t, "trace-2", spans[0].Refs()[1].GetTraceID(), "trace id of the second ref not correct")
+}
+
+type testNamesrvs struct {
+}
+
+func (n *testNamesrvs) FindBrokerAddrByName(brokerName string) string {
+	return "localhost:10911"
+}
+
+func (n *testNamesrvs) AddrList() []string {
+	return []string{"localhost:9876"}
+}
+
+type testClient struct {
+}
+
+func (c *testClient) GetNameSrv() nativeNamesrvs {
+	return &testNamesrvs{}
+}
+
+func newTestConsumer() *nativepushConsumer {
+	return &nativepushConsumer{nativedefaultConsumer: &nativedefaultConsumer{client: &testClient{}}}
+}
+
+func newTestMessage(header string) *primitive.MessageExt {
+	msg := &primitive.MessageExt{Message: primitive.Message{Topic: "test"}, MsgId: "msg", StoreHost: "localhost:10911"}
+	msg.WithProperty(core.Header, header)
+	return msg
+}
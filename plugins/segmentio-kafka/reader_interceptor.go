@@ -22,6 +22,7 @@ import (
 
 	"github.com/segmentio/kafka-go"
 
+	"github.com/apache/skywalking-go/plugins/core/log"
 	"github.com/apache/skywalking-go/plugins/core/operator"
 	"github.com/apache/skywalking-go/plugins/core/tracing"
 )
@@ -47,14 +48,18 @@ func (r *ReaderInterceptor) AfterInvoke(invocation operator.Invocation, result .
 	topic := message.Topic
 	operationName := kafkaReaderPrefix + topic + kafkaReaderSuffix
 
-	span, err := tracing.CreateEntrySpan(operationName, func(headerKey string) (string, error) {
+	extractor := func(headerKey string) (string, error) {
 		for _, header := range message.Headers {
 			if header.Key == headerKey {
 				return string(header.Value), nil
 			}
 		}
 		return "", nil
-	},
+	}
+	// the active entry span is reused when consuming the messages in a batch
+	active := tracing.ActiveSpan()
+	reused := active != nil && active.IsEntry()
+	span, err := tracing.CreateEntrySpan(operationName, extractor,
 		tracing.WithLayer(tracing.SpanLayerMQ),
 		tracing.WithComponent(kafkaReaderComponentID),
 		tracing.WithTag(tracing.TagMQBroker, brokers),
@@ -63,12 +68,21 @@ func (r *ReaderInterceptor) AfterInvoke(invocation operator.Invocation, result .
 	if err != nil {
 		return err
 	}
+	// the extractor is ignored when reusing, so link the consumed message to the batch span
+	if reused {
+		if err = span.AddRef(extractor); err != nil {
+			log.Warnf("cannot link the message to the consumer span: %v", err)
+		}
+	}
 
 	if err, ok := result[1].(error); ok {
 		span.Tag(tracing.TagMQStatus, err.Error())
 		span.Error(err.Error())
 	}
 	span.SetPeer(brokers)
-	span.End()
+	// the reused span is ended by the creator after the batch consumed
+	if !reused {
+		span.End()
+	}
 	return nil
 }
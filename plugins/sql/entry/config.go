@@ -17,7 +17,9 @@
 
 package entry
 
+import "github.com/apache/skywalking-go/plugins/core/tools"
+
 //skywalking:config
 var config struct {
-	CollectParameter bool `config:"collect_parameter"`
+	CollectParameter *tools.DynamicValue `config:"collect_parameter" dynamic:"bool"`
 }
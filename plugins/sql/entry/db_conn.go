@@ -51,7 +51,7 @@ func (n *ConnInterceptor) AfterInvoke(invocation operator.Invocation, results ..
 	}
 	// if contains error, then record it
 	if err, ok := results[1].(error); ok && err != nil {
-		ctx.(*connInfo).span.Error(err.Error())
+		ctx.(*connInfo).span.RecordError(err)
 	}
 	ctx.(*connInfo).span.End()
 
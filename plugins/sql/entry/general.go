@@ -50,7 +50,7 @@ func GeneralPrepareAfterInvoke(invocation operator.Invocation, results ...interf
 	}
 	// if contains error, then record it
 	if err, ok := results[1].(error); ok && err != nil {
-		ctx.(*PrepareInfo).span.Error(err.Error())
+		ctx.(*PrepareInfo).span.RecordError(err)
 	}
 	ctx.(*PrepareInfo).span.End()
 
@@ -85,7 +85,7 @@ func GeneralBeginTxAfterInvoke(invocation operator.Invocation, result ...interfa
 	}
 	// if contains error, then record it
 	if err, ok := result[1].(error); ok && err != nil {
-		ctx.(*BeginTxInfo).span.Error(err.Error())
+		ctx.(*BeginTxInfo).span.RecordError(err)
 	}
 	ctx.(*BeginTxInfo).span.End()
 
@@ -102,7 +102,7 @@ func GeneralExecBeforeInvoke(invocation operator.Invocation, method string) erro
 	if err != nil {
 		return err
 	}
-	if config.CollectParameter && len(invocation.Args()[2].([]interface{})) > 0 {
+	if config.CollectParameter.Bool() && len(invocation.Args()[2].([]interface{})) > 0 {
 		span.Tag(tracing.TagDBSqlParameters, argsToString(invocation.Args()[2].([]interface{})))
 	}
 	invocation.SetContext(span)
@@ -115,7 +115,7 @@ func GeneralExecAfterInvoke(invocation operator.Invocation, results ...interface
 		return nil
 	}
 	if err, ok := results[1].(error); ok && err != nil {
-		ctx.(tracing.Span).Error(err.Error())
+		ctx.(tracing.Span).RecordError(err)
 	}
 	ctx.(tracing.Span).End()
 	return nil
@@ -136,7 +136,7 @@ func GeneralPingAfterInvoke(invocation operator.Invocation, results ...interface
 		return nil
 	}
 	if err, ok := results[0].(error); ok && err != nil {
-		ctx.(tracing.Span).Error(err.Error())
+		ctx.(tracing.Span).RecordError(err)
 	}
 	ctx.(tracing.Span).End()
 	return nil
@@ -148,7 +148,7 @@ func GeneralQueryBeforeInvoke(invocation operator.Invocation, method string) err
 	if err != nil {
 		return err
 	}
-	if config.CollectParameter && len(invocation.Args()[2].([]interface{})) > 0 {
+	if config.CollectParameter.Bool() && len(invocation.Args()[2].([]interface{})) > 0 {
 		span.Tag(tracing.TagDBSqlParameters, argsToString(invocation.Args()[2].([]interface{})))
 	}
 	invocation.SetContext(span)
@@ -161,7 +161,7 @@ func GeneralQueryAfterInvoke(invocation operator.Invocation, results ...interfac
 		return nil
 	}
 	if err, ok := results[1].(error); ok && err != nil {
-		ctx.(tracing.Span).Error(err.Error())
+		ctx.(tracing.Span).RecordError(err)
 	}
 	ctx.(tracing.Span).End()
 	return nil
@@ -182,7 +182,7 @@ func GeneralRawAfterInvoke(invocation operator.Invocation, results ...interface{
 		return nil
 	}
 	if err, ok := results[1].(error); ok && err != nil {
-		ctx.(tracing.Span).Error(err.Error())
+		ctx.(tracing.Span).RecordError(err)
 	}
 	ctx.(tracing.Span).End()
 	return nil
@@ -30,7 +30,7 @@ func (n *StmtExecInterceptor) BeforeInvoke(invocation operator.Invocation) error
 	if err != nil {
 		return err
 	}
-	if config.CollectParameter && len(invocation.Args()[1].([]interface{})) > 0 {
+	if config.CollectParameter.Bool() && len(invocation.Args()[1].([]interface{})) > 0 {
 		span.Tag(tracing.TagDBSqlParameters, argsToString(invocation.Args()[1].([]interface{})))
 	}
 	invocation.SetContext(span)
@@ -43,7 +43,7 @@ func (n *StmtExecInterceptor) AfterInvoke(invocation operator.Invocation, result
 		return nil
 	}
 	if err, ok := results[1].(error); ok && err != nil {
-		ctx.(tracing.Span).Error(err.Error())
+		ctx.(tracing.Span).RecordError(err)
 	}
 	ctx.(tracing.Span).End()
 	return nil
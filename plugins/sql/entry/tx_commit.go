@@ -40,7 +40,7 @@ func (n *TxCommitInterceptor) AfterInvoke(invocation operator.Invocation, result
 		return nil
 	}
 	if err, ok := results[0].(error); ok && err != nil {
-		ctx.(tracing.Span).Error(err.Error())
+		ctx.(tracing.Span).RecordError(err)
 	}
 	ctx.(tracing.Span).End()
 	return nil
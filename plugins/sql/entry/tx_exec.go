@@ -31,7 +31,7 @@ func (n *TxExecInterceptor) BeforeInvoke(invocation operator.Invocation) error {
 	if err != nil {
 		return err
 	}
-	if config.CollectParameter && len(invocation.Args()[2].([]interface{})) > 0 {
+	if config.CollectParameter.Bool() && len(invocation.Args()[2].([]interface{})) > 0 {
 		span.Tag(tracing.TagDBSqlParameters, argsToString(invocation.Args()[2].([]interface{})))
 	}
 	invocation.SetContext(span)
@@ -44,7 +44,7 @@ func (n *TxExecInterceptor) AfterInvoke(invocation operator.Invocation, results
 		return nil
 	}
 	if err, ok := results[1].(error); ok && err != nil {
-		ctx.(tracing.Span).Error(err.Error())
+		ctx.(tracing.Span).RecordError(err)
 	}
 	ctx.(tracing.Span).End()
 	return nil
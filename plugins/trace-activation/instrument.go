@@ -63,6 +63,26 @@ func (i *Instrument) Points() []*instrument.Point {
 			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("CreateExitSpan"),
 			Interceptor: "CreateExitSpanInterceptor",
 		},
+		{
+			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("CreateEntrySpanWithContext"),
+			Interceptor: "CreateEntrySpanWithContextInterceptor",
+		},
+		{
+			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("CreateLocalSpanWithContext"),
+			Interceptor: "CreateLocalSpanWithContextInterceptor",
+		},
+		{
+			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("CreateExitSpanWithContext"),
+			Interceptor: "CreateExitSpanWithContextInterceptor",
+		},
+		{
+			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("ContextWithSpan"),
+			Interceptor: "ContextWithSpanInterceptor",
+		},
+		{
+			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("SpanFromContext"),
+			Interceptor: "SpanFromContextInterceptor",
+		},
 		{
 			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("StopSpan"),
 			Interceptor: "StopSpanInterceptor",
@@ -95,10 +115,22 @@ func (i *Instrument) Points() []*instrument.Point {
 			PackagePath: "trace", At: instrument.NewMethodEnhance("*SpanRef", "AddLog"),
 			Interceptor: "AsyncLogInterceptor",
 		},
+		{
+			PackagePath: "trace", At: instrument.NewMethodEnhance("*SpanRef", "AddLogFields"),
+			Interceptor: "AsyncLogFieldsInterceptor",
+		},
+		{
+			PackagePath: "trace", At: instrument.NewMethodEnhance("*SpanRef", "RecordError"),
+			Interceptor: "AsyncRecordErrorInterceptor",
+		},
 		{
 			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("AddLog"),
 			Interceptor: "AddLogInterceptor",
 		},
+		{
+			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("AddLogFields"),
+			Interceptor: "AddLogFieldsInterceptor",
+		},
 		{
 			PackagePath: "trace", At: instrument.NewStaticMethodEnhance("SetTag"),
 			Interceptor: "SetTagInterceptor",
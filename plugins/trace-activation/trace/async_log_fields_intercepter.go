@@ -0,0 +1,58 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package traceactivation
+
+import (
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+	"github.com/apache/skywalking-go/toolkit/trace"
+)
+
+type AsyncLogFieldsInterceptor struct {
+}
+
+func (h *AsyncLogFieldsInterceptor) BeforeInvoke(invocation operator.Invocation) error {
+	return nil
+}
+
+func (h *AsyncLogFieldsInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
+	enhancced, ok := invocation.CallerInstance().(operator.EnhancedInstance)
+	if !ok {
+		return nil
+	}
+	s := enhancced.GetSkyWalkingDynamicField().(tracing.Span)
+	fields, opts := toLogFields(invocation.Args()[0].([]trace.LogField), invocation.Args()[1].([]trace.LogOption))
+	s.LogFields(fields, opts...)
+	enhancced.SetSkyWalkingDynamicField(s)
+	return nil
+}
+
+func toLogFields(fields []trace.LogField, opts []trace.LogOption) ([]tracing.Field, []tracing.LogOption) {
+	result := make([]tracing.Field, 0, len(fields))
+	for _, f := range fields {
+		result = append(result, tracing.Field{Key: f.Key, Value: f.Value})
+	}
+	options := &trace.LogOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Time.IsZero() {
+		return result, nil
+	}
+	return result, []tracing.LogOption{tracing.WithLogTime(options.Time)}
+}
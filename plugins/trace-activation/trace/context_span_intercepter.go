@@ -0,0 +1,78 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package traceactivation
+
+import (
+	"context"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+	"github.com/apache/skywalking-go/toolkit/trace"
+)
+
+type ContextWithSpanInterceptor struct {
+}
+
+func (h *ContextWithSpanInterceptor) BeforeInvoke(invocation operator.Invocation) error {
+	return nil
+}
+
+func (h *ContextWithSpanInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
+	ctx, ok := invocation.Args()[0].(context.Context)
+	if !ok || ctx == nil {
+		return nil
+	}
+	enhancced, ok := invocation.Args()[1].(operator.EnhancedInstance)
+	if !ok {
+		return nil
+	}
+	span, ok := enhancced.GetSkyWalkingDynamicField().(tracing.Span)
+	if !ok {
+		return nil
+	}
+	if spanCtx, ok := tracing.ContextWithSpan(ctx, span).(context.Context); ok {
+		invocation.DefineReturnValues(spanCtx)
+	}
+	return nil
+}
+
+type SpanFromContextInterceptor struct {
+}
+
+func (h *SpanFromContextInterceptor) BeforeInvoke(invocation operator.Invocation) error {
+	return nil
+}
+
+func (h *SpanFromContextInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
+	ctx, ok := invocation.Args()[0].(context.Context)
+	if !ok || ctx == nil {
+		return nil
+	}
+	span := tracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+	ref := &trace.SpanRef{}
+	enhancced, ok := interface{}(ref).(operator.EnhancedInstance)
+	if !ok {
+		return nil
+	}
+	enhancced.SetSkyWalkingDynamicField(span)
+	invocation.DefineReturnValues(ref)
+	return nil
+}
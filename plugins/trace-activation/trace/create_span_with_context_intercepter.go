@@ -0,0 +1,84 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package traceactivation
+
+import (
+	"context"
+
+	"github.com/apache/skywalking-go/plugins/core/operator"
+	"github.com/apache/skywalking-go/plugins/core/tracing"
+	"github.com/apache/skywalking-go/toolkit/trace"
+)
+
+type CreateEntrySpanWithContextInterceptor struct {
+}
+
+func (h *CreateEntrySpanWithContextInterceptor) BeforeInvoke(invocation operator.Invocation) error {
+	return nil
+}
+
+func (h *CreateEntrySpanWithContextInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
+	ctx, _ := invocation.Args()[0].(context.Context)
+	operationName := invocation.Args()[1].(string)
+	var extractor func(headerKey string) (string, error) = invocation.Args()[2].(trace.ExtractorRef)
+	s, err := tracing.CreateEntrySpanWithContext(ctx, operationName, extractor)
+	return saveCreatedSpan(invocation, result, s, err)
+}
+
+type CreateLocalSpanWithContextInterceptor struct {
+}
+
+func (h *CreateLocalSpanWithContextInterceptor) BeforeInvoke(invocation operator.Invocation) error {
+	return nil
+}
+
+func (h *CreateLocalSpanWithContextInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
+	ctx, _ := invocation.Args()[0].(context.Context)
+	operationName := invocation.Args()[1].(string)
+	s, err := tracing.CreateLocalSpanWithContext(ctx, operationName)
+	return saveCreatedSpan(invocation, result, s, err)
+}
+
+type CreateExitSpanWithContextInterceptor struct {
+}
+
+func (h *CreateExitSpanWithContextInterceptor) BeforeInvoke(invocation operator.Invocation) error {
+	return nil
+}
+
+func (h *CreateExitSpanWithContextInterceptor) AfterInvoke(invocation operator.Invocation, result ...interface{}) error {
+	ctx, _ := invocation.Args()[0].(context.Context)
+	operationName := invocation.Args()[1].(string)
+	peer := invocation.Args()[2].(string)
+	var injector func(headerKey, headerValue string) error = invocation.Args()[3].(trace.InjectorRef)
+	s, err := tracing.CreateExitSpanWithContext(ctx, operationName, peer, injector)
+	return saveCreatedSpan(invocation, result, s, err)
+}
+
+func saveCreatedSpan(invocation operator.Invocation, result []interface{}, s tracing.Span, err error) error {
+	if err != nil {
+		invocation.DefineReturnValues(nil, err)
+		return nil
+	}
+	enhancced, ok := result[0].(operator.EnhancedInstance)
+	if !ok {
+		return nil
+	}
+	enhancced.SetSkyWalkingDynamicField(s)
+	return nil
+}
@@ -17,6 +17,8 @@
 
 package trace
 
+import "context"
+
 type ExtractorRef func(headerKey string) (string, error)
 
 type InjectorRef func(headerKey, headerValue string) error
@@ -40,6 +42,33 @@ func CreateLocalSpan(operationName string) (s *SpanRef, err error) {
 	return &SpanRef{}, err
 }
 
+// CreateEntrySpanWithContext creates the entry span, the span carried by the ctx is the parent if exists.
+func CreateEntrySpanWithContext(ctx context.Context, operationName string, extractor ExtractorRef) (s *SpanRef, err error) {
+	return &SpanRef{}, err
+}
+
+// CreateExitSpanWithContext creates the exit span, the span carried by the ctx is the parent if exists.
+// nolint
+func CreateExitSpanWithContext(ctx context.Context, operationName string, peer string, injector InjectorRef) (s *SpanRef, err error) {
+	return &SpanRef{}, err
+}
+
+// CreateLocalSpanWithContext creates the local span, the span carried by the ctx is the parent if exists.
+func CreateLocalSpanWithContext(ctx context.Context, operationName string) (s *SpanRef, err error) {
+	return &SpanRef{}, err
+}
+
+// ContextWithSpan returns a copy of the ctx which carries the span,
+// then the span could be the parent of the spans created with the ctx in any goroutine.
+func ContextWithSpan(ctx context.Context, span *SpanRef) context.Context {
+	return ctx
+}
+
+// SpanFromContext returns the span carried by the ctx, or nil if not exists.
+func SpanFromContext(ctx context.Context) *SpanRef {
+	return nil
+}
+
 func StopSpan() {
 }
 
@@ -72,6 +101,10 @@ func SetTag(key string, value string) {
 func AddLog(...string) {
 }
 
+// AddLogFields add the typed fields as a log of the active span.
+func AddLogFields(fields []LogField, opts ...LogOption) {
+}
+
 func GetCorrelation(key string) string {
 	return ""
 }
@@ -17,6 +17,8 @@
 
 package trace
 
+import "time"
+
 func (*SpanRef) PrepareAsync() {
 }
 
@@ -29,3 +31,73 @@ func (*SpanRef) SetTag(key string, value string) {
 
 func (*SpanRef) AddLog(...string) {
 }
+
+// AddLogFields add the typed fields as a log of the span, the log time could be given by WithLogTime.
+func (*SpanRef) AddLogFields(fields []LogField, opts ...LogOption) {
+}
+
+// RecordError add the error as a structured log of the span, and mark the span as error.
+func (*SpanRef) RecordError(err error, opts ...ErrorOption) {
+}
+
+// ErrorOption adjusts the error recorded by RecordError.
+type ErrorOption func(*ErrorOptions)
+
+// ErrorOptions are the options of recording error.
+type ErrorOptions struct {
+	WithStack bool
+}
+
+// WithErrorStack records the stack of the current goroutine with the error.
+func WithErrorStack() ErrorOption {
+	return func(o *ErrorOptions) {
+		o.WithStack = true
+	}
+}
+
+// LogField is a typed key/value pair of the structured log.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// StringField builds a string LogField.
+func StringField(key, value string) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+// IntField builds an integer LogField.
+func IntField(key string, value int64) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+// FloatField builds a float LogField.
+func FloatField(key string, value float64) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+// BoolField builds a boolean LogField.
+func BoolField(key string, value bool) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+// ErrorField builds a LogField with the message of the error.
+func ErrorField(key string, err error) LogField {
+	return LogField{Key: key, Value: err}
+}
+
+// LogOption adjusts the log recorded by AddLogFields.
+type LogOption func(*LogOptions)
+
+// LogOptions are the options of recording log.
+type LogOptions struct {
+	Time time.Time
+}
+
+// WithLogTime records the log at the given time instead of the current time,
+// such as the events measured earlier.
+func WithLogTime(t time.Time) LogOption {
+	return func(o *LogOptions) {
+		o.Time = t
+	}
+}
@@ -45,18 +45,33 @@ type Config struct {
 }
 
 type Agent struct {
-	ServiceName     StringValue `yaml:"service_name"`
-	InstanceEnvName StringValue `yaml:"instance_env_name"`
-	Sampler         StringValue `yaml:"sampler"`
-	Meter           Meter       `yaml:"meter"`
-	Correlation     Correlation `yaml:"correlation"`
-	IgnoreSuffix    StringValue `yaml:"ignore_suffix"`
-	TraceIgnorePath StringValue `yaml:"trace_ignore_path"`
+	ServiceName                 StringValue      `yaml:"service_name"`
+	InstanceEnvName             StringValue      `yaml:"instance_env_name"`
+	Sampler                     StringValue      `yaml:"sampler"`
+	SamplerRules                StringValue      `yaml:"sampler_rules"`
+	SamplerType                 StringValue      `yaml:"sampler_type"`
+	SamplerRateLimit            SamplerRateLimit `yaml:"sampler_rate_limit"`
+	HonorUpstreamSampling       StringValue      `yaml:"honor_upstream_sampling"`
+	Meter                       Meter            `yaml:"meter"`
+	Correlation                 Correlation      `yaml:"correlation"`
+	IgnoreSuffix                StringValue      `yaml:"ignore_suffix"`
+	TraceIgnorePath             StringValue      `yaml:"trace_ignore_path"`
+	SpanLimitPerSegment         StringValue      `yaml:"span_limit_per_segment"`
+	KeepTracingWhenDisconnected StringValue      `yaml:"keep_tracing_when_disconnected"`
+	SpanData                    SpanData         `yaml:"span_data"`
+	TailSampling                TailSampling     `yaml:"tail_sampling"`
+	SegmentProcessors           StringValue      `yaml:"segment_processors"`
+	EndpointNormalization       Normalization    `yaml:"endpoint_normalization"`
+	DynamicConfigFile           ConfigFileSource `yaml:"dynamic_config_file"`
+	Shutdown                    Shutdown         `yaml:"shutdown"`
 }
 
 type Reporter struct {
 	Discard StringValue  `yaml:"discard"`
 	GRPC    GRPCReporter `yaml:"grpc"`
+	File    FileReporter `yaml:"file"`
+	HTTP    HTTPReporter `yaml:"http"`
+	OTLP    OTLPReporter `yaml:"otlp"`
 }
 
 type Log struct {
@@ -73,6 +88,7 @@ type LogTracing struct {
 type LogReporter struct {
 	Enabled   StringValue `yaml:"enable"`
 	LabelKeys StringValue `yaml:"label_keys"`
+	Level     StringValue `yaml:"level"`
 }
 
 type Meter struct {
@@ -80,12 +96,41 @@ type Meter struct {
 }
 
 type GRPCReporter struct {
-	BackendService   StringValue     `yaml:"backend_service"`
-	MaxSendQueue     StringValue     `yaml:"max_send_queue"`
-	CheckInterval    StringValue     `yaml:"check_interval"`
-	Authentication   StringValue     `yaml:"authentication"`
-	CDSFetchInterval StringValue     `yaml:"cds_fetch_interval"`
-	TLS              GRPCReporterTLS `yaml:"tls"`
+	BackendService   StringValue       `yaml:"backend_service"`
+	MaxSendQueue     StringValue       `yaml:"max_send_queue"`
+	CheckInterval    StringValue       `yaml:"check_interval"`
+	Authentication   StringValue       `yaml:"authentication"`
+	CDSFetchInterval StringValue       `yaml:"cds_fetch_interval"`
+	TLS              GRPCReporterTLS   `yaml:"tls"`
+	Spool            GRPCReporterSpool `yaml:"spool"`
+	Compressor       StringValue       `yaml:"compressor"`
+	SegmentBatch     GRPCSegmentBatch  `yaml:"segment_batch"`
+	StreamRecycle    StringValue       `yaml:"stream_recycle"`
+	Queue            GRPCSendQueues    `yaml:"queue"`
+}
+
+type GRPCSendQueues struct {
+	Segment GRPCSendQueue `yaml:"segment"`
+	Meter   GRPCSendQueue `yaml:"meter"`
+	Log     GRPCSendQueue `yaml:"log"`
+}
+
+type GRPCSendQueue struct {
+	Size         StringValue `yaml:"size"`
+	Policy       StringValue `yaml:"policy"`
+	BlockTimeout StringValue `yaml:"block_timeout"`
+}
+
+type GRPCSegmentBatch struct {
+	Size     StringValue `yaml:"size"`
+	Interval StringValue `yaml:"interval"`
+}
+
+type GRPCReporterSpool struct {
+	Enable  StringValue `yaml:"enable"`
+	Path    StringValue `yaml:"path"`
+	MaxSize StringValue `yaml:"max_size"`
+	MaxAge  StringValue `yaml:"max_age"`
 }
 
 type GRPCReporterTLS struct {
@@ -96,11 +141,67 @@ type GRPCReporterTLS struct {
 	InsecureSkipVerify  StringValue `yaml:"insecure_skip_verify"`
 }
 
+type FileReporter struct {
+	Enable       StringValue `yaml:"enable"`
+	Path         StringValue `yaml:"path"`
+	MaxFileSize  StringValue `yaml:"max_file_size"`
+	MaxBackups   StringValue `yaml:"max_backups"`
+	MaxSendQueue StringValue `yaml:"max_send_queue"`
+}
+
+type HTTPReporter struct {
+	Enable         StringValue `yaml:"enable"`
+	BackendService StringValue `yaml:"backend_service"`
+	Timeout        StringValue `yaml:"timeout"`
+}
+
+type OTLPReporter struct {
+	Enable   StringValue     `yaml:"enable"`
+	Endpoint StringValue     `yaml:"endpoint"`
+	Protocol StringValue     `yaml:"protocol"`
+	Headers  StringValue     `yaml:"headers"`
+	Timeout  StringValue     `yaml:"timeout"`
+	TLS      GRPCReporterTLS `yaml:"tls"`
+}
+
 type Plugin struct {
 	Config   PluginConfig `yaml:"config"`
 	Excluded StringValue  `yaml:"excluded"`
 }
 
+type Shutdown struct {
+	Timeout    StringValue `yaml:"timeout"`
+	SignalHook StringValue `yaml:"signal_hook"`
+}
+
+type SpanData struct {
+	MaxTagCount     StringValue `yaml:"max_tag_count"`
+	MaxTagValueSize StringValue `yaml:"max_tag_value_size"`
+	MaxLogCount     StringValue `yaml:"max_log_count"`
+	MaxLogValueSize StringValue `yaml:"max_log_value_size"`
+}
+
+type SamplerRateLimit struct {
+	TracesPerWindow StringValue `yaml:"traces_per_window"`
+	Window          StringValue `yaml:"window"`
+}
+
+type TailSampling struct {
+	Enable                 StringValue `yaml:"enable"`
+	SlowThreshold          StringValue `yaml:"slow_threshold"`
+	EndpointSlowThresholds StringValue `yaml:"endpoint_slow_thresholds"`
+}
+
+type Normalization struct {
+	AutoCollapse StringValue `yaml:"auto_collapse"`
+	Rules        StringValue `yaml:"rules"`
+}
+
+type ConfigFileSource struct {
+	Path          StringValue `yaml:"path"`
+	CheckInterval StringValue `yaml:"check_interval"`
+}
+
 type Correlation struct {
 	MaxKeyCount  StringValue `yaml:"max_key_count"`
 	MaxValueSize StringValue `yaml:"max_value_size"`
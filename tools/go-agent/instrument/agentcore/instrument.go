@@ -161,6 +161,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	_ "unsafe"
 )
 
@@ -174,7 +175,11 @@ func (t *Tracer) InitTracer(extend map[string]interface{}) {
 		return
 	}
 	entity := NewEntity({{.Config.Agent.ServiceName.ToGoStringValue}}, {{.Config.Agent.InstanceEnvName.ToGoStringValue}})
-	samp := NewDynamicSampler({{.Config.Agent.Sampler.ToGoFloatValue "loading the agent sampler error"}}, t)
+	samp := NewDynamicSampler({{.Config.Agent.Sampler.ToGoFloatValue "loading the agent sampler error"}},
+		{{.Config.Agent.SamplerRules.ToGoStringValue}}, t)
+	samp.InitSamplerType({{.Config.Agent.SamplerType.ToGoStringValue}},
+		{{.Config.Agent.SamplerRateLimit.TracesPerWindow.ToGoIntValue "loading the agent sampler rate limit traces per window error"}},
+		{{.Config.Agent.SamplerRateLimit.Window.ToGoIntValue "loading the agent sampler rate limit window error"}})
 	meterCollectInterval := {{.Config.Agent.Meter.CollectInterval.ToGoIntValue "loading the agent meter interval error"}}
 	var logger operator.LogOperator
 	if {{.GetGlobalLoggerLinkMethod}} != nil {
@@ -188,9 +193,29 @@ func (t *Tracer) InitTracer(extend map[string]interface{}) {
 	}
 	ignoreSuffixStr := {{.Config.Agent.IgnoreSuffix.ToGoStringValue}}
 	ignorePath := {{.Config.Agent.TraceIgnorePath.ToGoStringValue}}
+	t.InitLogReporter({{.Config.Log.Reporter.Enabled.ToGoBoolValue}}, {{.Config.Log.Reporter.Level.ToGoStringValue}})
+	t.InitSegmentProcessors({{.Config.Agent.SegmentProcessors.ToGoStringValue}})
+	t.InitEndpointNormalization({{.Config.Agent.EndpointNormalization.AutoCollapse.ToGoBoolValue}},
+		{{.Config.Agent.EndpointNormalization.Rules.ToGoStringValue}})
 	if err := t.Init(entity, rep, samp, logger, meterCollectInterval, correlation, ignoreSuffixStr, ignorePath); err != nil {
 		t.Log.Errorf("cannot initialize the SkyWalking Tracer: %v", err)
 	}
+	t.InitSpanLimit({{.Config.Agent.SpanLimitPerSegment.ToGoIntValue "loading the agent span limit per segment error"}})
+	t.InitKeepTracingWhenDisconnected({{.Config.Agent.KeepTracingWhenDisconnected.ToGoBoolValue}})
+	t.InitSpanDataLimit(&SpanDataLimitConfig{
+		MaxTagCount: {{.Config.Agent.SpanData.MaxTagCount.ToGoIntValue "loading the agent span data max tag count error"}},
+		MaxTagValueSize: {{.Config.Agent.SpanData.MaxTagValueSize.ToGoIntValue "loading the agent span data max tag value size error"}},
+		MaxLogCount: {{.Config.Agent.SpanData.MaxLogCount.ToGoIntValue "loading the agent span data max log count error"}},
+		MaxLogValueSize: {{.Config.Agent.SpanData.MaxLogValueSize.ToGoIntValue "loading the agent span data max log value size error"}},
+	})
+	t.InitHonorUpstreamSampling({{.Config.Agent.HonorUpstreamSampling.ToGoBoolValue}})
+	t.InitTailSampling({{.Config.Agent.TailSampling.Enable.ToGoBoolValue}},
+		{{.Config.Agent.TailSampling.SlowThreshold.ToGoIntValue "loading the agent tail sampling slow threshold error"}},
+		{{.Config.Agent.TailSampling.EndpointSlowThresholds.ToGoStringValue}})
+	t.InitDynamicConfigFile({{.Config.Agent.DynamicConfigFile.Path.ToGoStringValue}},
+		{{.Config.Agent.DynamicConfigFile.CheckInterval.ToGoIntValue "loading the agent dynamic config file check interval error"}})
+	t.InitShutdown({{.Config.Agent.Shutdown.Timeout.ToGoIntValue "loading the agent shutdown timeout error"}},
+		{{.Config.Agent.Shutdown.SignalHook.ToGoBoolValue}})
 }`, struct {
 		GRPCReporterFuncName      string
 		GetGlobalLoggerLinkMethod string
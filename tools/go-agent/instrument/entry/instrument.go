@@ -40,6 +40,11 @@ func (i *Instrument) CouldHandle(opts *api.CompileOptions) bool {
 }
 
 func (i *Instrument) FilterAndEdit(path string, curFile *dst.File, cursor *dstutil.Cursor, allFiles []*dst.File) bool {
+	// flush the pending data of agent when the main function returned
+	if fn, ok := cursor.Node().(*dst.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" && fn.Body != nil {
+		tools.InsertStmtsBeforeBody(fn.Body, `defer skywalkingShutdownTracer()`, nil)
+		return true
+	}
 	if i.hasFound {
 		return false
 	}
@@ -65,6 +70,10 @@ type skywalkingTracerInitiator interface {
 	InitTracer(map[string]interface{})
 }
 
+type skywalkingTracerShutdowner interface {
+	ShutdownTracer()
+}
+
 func init() {
 	if {{.GetGlobalOperatorLinkMethod}} != nil {
 		op := {{.GetGlobalOperatorLinkMethod}}()
@@ -78,6 +87,15 @@ func init() {
 		tracer.InitTracer(nil)
 	}
 }
+
+func skywalkingShutdownTracer() {
+	if {{.GetGlobalOperatorLinkMethod}} == nil {
+		return
+	}
+	if tracer, ok := {{.GetGlobalOperatorLinkMethod}}().(skywalkingTracerShutdowner); ok {
+		tracer.ShutdownTracer()
+	}
+}
 `, struct {
 		GetGlobalOperatorLinkMethod string
 		Config                      *config.Config
@@ -33,8 +33,13 @@ import (
 )
 
 var (
-	configFieldKeyTag = "config"
-	toolsImports      = "github.com/apache/skywalking-go/plugins/core/tools"
+	configFieldKeyTag     = "config"
+	configFieldDynamicTag = "dynamic"
+	toolsImports          = "github.com/apache/skywalking-go/plugins/core/tools"
+	// the type of the dynamic config field, which holds the value safe for reading when changing
+	dynamicConfigFieldType = "*tools.DynamicValue"
+	// the key prefix of the plugin configs in the dynamic configuration
+	dynamicConfigKeyPrefix = "plugin.config."
 )
 
 type ConfigEnhance struct {
@@ -120,6 +125,7 @@ type ConfigField struct {
 	Name        string
 	Type        string
 	Key         string
+	Dynamic     bool
 	ChildFields []*ConfigField
 }
 
@@ -149,7 +155,7 @@ func NewConfigField(f *dst.Field) (*ConfigField, error) {
 	switch t := f.Type.(type) {
 	case *dst.Ident:
 		conf.Type = t.Name
-	case *dst.ArrayType:
+	case *dst.ArrayType, *dst.StarExpr:
 		conf.Type = tools.GenerateTypeNameByExp(t)
 	case *dst.StructType:
 		fs, err := NewConfigFields(t)
@@ -160,7 +166,13 @@ func NewConfigField(f *dst.Field) (*ConfigField, error) {
 	default:
 		return nil, fmt.Errorf("the config structure field %s type %T is not supported", conf.Name, t)
 	}
+	fieldType := conf.Type
 	conf.initFlags(f.Tag)
+	if conf.Dynamic && fieldType != dynamicConfigFieldType {
+		return nil, fmt.Errorf("the dynamic config structure field %s type must be %s", conf.Name, dynamicConfigFieldType)
+	} else if !conf.Dynamic && strings.HasPrefix(fieldType, "*") {
+		return nil, fmt.Errorf("the config structure field %s type %s is not supported", conf.Name, fieldType)
+	}
 	return conf, nil
 }
 
@@ -170,7 +182,12 @@ func (f *ConfigField) initFlags(flagLit *dst.BasicLit) {
 		return
 	}
 
-	tag := reflect.StructTag(flagLit.Value)
+	tag := reflect.StructTag(strings.Trim(flagLit.Value, "`"))
+	// the dynamic tag declares the value type of the dynamic config
+	if valueType := tag.Get(configFieldDynamicTag); valueType != "" {
+		f.Dynamic = true
+		f.Type = valueType
+	}
 	value, ok := tag.Lookup(configFieldKeyTag)
 	if ok {
 		f.Key = value
@@ -242,7 +259,36 @@ func (f *ConfigField) GenerateAssignFieldValue(varName string, field, path []str
 	default:
 		panic("unsupported config type " + f.Type)
 	}
-	stmtStr := fmt.Sprintf("%s.%s = func () %s { result := %q; %s%s }()",
-		varName, fieldPathStr, resultType, pluginConfig.Default, getFromEnvStr, parseResStr)
+	valueStr := fmt.Sprintf("func () %s { result := %q; %s%s }()", resultType, pluginConfig.Default, getFromEnvStr, parseResStr)
+	if f.Dynamic {
+		valueStr = fmt.Sprintf("tools.NewDynamicValue(%s)", valueStr)
+	}
+	stmtStr := fmt.Sprintf("%s.%s = %s", varName, fieldPathStr, valueStr)
+	if f.Dynamic {
+		stmtStr += ";" + f.generateWatchConfig(varName, fieldPathStr, fieldKeyPathStr, pluginConfig.Default, getFromEnvStr)
+	}
 	return tools.GoStringToStats(stmtStr)
 }
+
+// generateWatchConfig registers the watcher for changing the field value through the dynamic configuration,
+// the parse error is returned instead of panic, so the invalid value would be ignored
+func (f *ConfigField) generateWatchConfig(varName, fieldPathStr, fieldKeyPathStr, defaultValue, getFromEnvStr string) string {
+	assignStr := fmt.Sprintf("%s.%s.Store(v); return nil", varName, fieldPathStr)
+	applyStr := ""
+	switch f.Type {
+	case "string":
+		applyStr = "v := result; " + assignStr
+	case "bool":
+		applyStr = "v := tools.ParseBool(result); " + assignStr
+	case "int":
+		applyStr = "v, err := tools.Atoi(result); if err != nil { return err }; " + assignStr
+	case "float64":
+		applyStr = "v, err := tools.ParseFloat(result, 64); if err != nil { return err }; " + assignStr
+	case "[]string":
+		applyStr = "v, err := tools.ParseStringArray(result); if err != nil { return err }; " + assignStr
+	default:
+		panic(fmt.Errorf("unsupported dynamic config type %s of the config %s", f.Type, fieldKeyPathStr))
+	}
+	return fmt.Sprintf("tools.WatchConfig(%q, func() string { result := %q; %sreturn result }(), func(result string) error { %s })",
+		dynamicConfigKeyPrefix+fieldKeyPathStr, defaultValue, getFromEnvStr, applyStr)
+}
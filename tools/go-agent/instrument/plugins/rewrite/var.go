@@ -34,8 +34,26 @@ func (c *Context) Var(val *dst.ValueSpec, onlyName bool) {
 	if onlyName {
 		return
 	}
-	c.enhanceTypeNameWhenRewrite(val.Type, val, -1)
+	// define the anonymous struct type, ex: "var xx struct {}"
+	if structType, ok := val.Type.(*dst.StructType); ok {
+		c.enhanceAnonymousStructFields(structType)
+	} else {
+		c.enhanceTypeNameWhenRewrite(val.Type, val, -1)
+	}
 	for _, subVal := range val.Values {
 		c.enhanceTypeNameWhenRewrite(subVal, val, -1)
 	}
 }
+
+func (c *Context) enhanceAnonymousStructFields(structType *dst.StructType) {
+	if structType.Fields == nil {
+		return
+	}
+	for _, field := range structType.Fields.List {
+		if child, ok := field.Type.(*dst.StructType); ok {
+			c.enhanceAnonymousStructFields(child)
+			continue
+		}
+		c.enhanceTypeNameWhenRewrite(field.Type, field, -1)
+	}
+}
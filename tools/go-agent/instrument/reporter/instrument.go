@@ -34,6 +34,9 @@ import (
 	"github.com/dave/dst/dstutil"
 )
 
+// reporterImplementations are the sub packages of the core reporter, all of them are copied into the agent reporter package
+var reporterImplementations = []string{"grpc", "file", "http", "otlp"}
+
 type GRPCInstrument struct {
 	hasToEnhance bool
 	compileOpts  *api.CompileOptions
@@ -77,9 +80,28 @@ func (i *GRPCInstrument) WriteExtraFiles(dir string) ([]string, error) {
 	results = append(results, copiedFiles...)
 
 	// copy reporter implementations
+	for _, impl := range reporterImplementations {
+		copiedFiles, err = i.copyReporterImplementation(impl, dir)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, copiedFiles...)
+	}
+
+	// generate the file for export the reporter
+	file, err := i.generateReporterInitFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, file)
+
+	return results, nil
+}
+
+func (i *GRPCInstrument) copyReporterImplementation(impl, dir string) ([]string, error) {
 	// Force the use of '/' delimiter on all platforms
-	reporterDirName := strings.ReplaceAll(filepath.Join("reporter", "grpc"), `\`, `/`)
-	copiedFiles, err = tools.CopyGoFiles(core.FS, reporterDirName, dir, func(entry fs.DirEntry, f *dst.File) (*tools.DebugInfo, error) {
+	reporterDirName := strings.ReplaceAll(filepath.Join("reporter", impl), `\`, `/`)
+	return tools.CopyGoFiles(core.FS, reporterDirName, dir, func(entry fs.DirEntry, f *dst.File) (*tools.DebugInfo, error) {
 		if i.compileOpts.DebugDir == "" {
 			return nil, nil
 		}
@@ -96,19 +118,6 @@ func (i *GRPCInstrument) WriteExtraFiles(dir string) ([]string, error) {
 		tools.ChangePackageImportPath(file, pkgUpdates)
 		tools.DeletePackageImports(file, "github.com/apache/skywalking-go/plugins/core/reporter")
 	})
-	if err != nil {
-		return nil, err
-	}
-	results = append(results, copiedFiles...)
-
-	// generate the file for export the reporter
-	file, err := i.generateReporterInitFile(dir)
-	if err != nil {
-		return nil, err
-	}
-	results = append(results, file)
-
-	return results, nil
 }
 
 func (i *GRPCInstrument) generateReporterInitFile(dir string) (string, error) {
@@ -127,10 +136,78 @@ func {{.InitFuncName}}(logger operator.LogOperator) (Reporter, error) {
 	if {{.Config.Reporter.Discard.ToGoBoolValue}} {
 		return NewDiscardReporter(), nil
 	}
+	if {{.Config.Reporter.File.Enable.ToGoBoolValue}} {
+		var fileOpts []FileReporterOption
+		fileMaxSizeVal := {{.Config.Reporter.File.MaxFileSize.ToGoIntValue "the file reporter max file size must be number"}}
+		fileOpts = append(fileOpts, WithFileMaxSize(int64(fileMaxSizeVal) * 1024 * 1024))
+		fileOpts = append(fileOpts, WithFileMaxBackups({{.Config.Reporter.File.MaxBackups.ToGoIntValue "the file reporter max backups must be number"}}))
+		fileOpts = append(fileOpts, WithFileMaxSendQueueSize({{.Config.Reporter.File.MaxSendQueue.ToGoIntValue "the file reporter max queue size must be number"}}))
+		return NewFileReporter(logger, {{.Config.Reporter.File.Path.ToGoStringValue}}, fileOpts...)
+	}
+	if {{.Config.Reporter.HTTP.Enable.ToGoBoolValue}} {
+		var httpOpts []HTTPReporterOption
+		httpCheckIntervalVal := {{.Config.Reporter.GRPC.CheckInterval.ToGoIntValue "the GRPC reporter check interval must be number"}}
+		httpOpts = append(httpOpts, WithHTTPCheckInterval(time.Second * time.Duration(httpCheckIntervalVal)))
+		httpOpts = append(httpOpts, WithHTTPMaxSendQueueSize({{.Config.Reporter.GRPC.MaxSendQueue.ToGoIntValue "the GRPC reporter max queue size must be number"}}))
+		httpOpts = append(httpOpts, WithHTTPAuthentication({{.Config.Reporter.GRPC.Authentication.ToGoStringValue}}))
+		httpTimeoutVal := {{.Config.Reporter.HTTP.Timeout.ToGoIntValue "the HTTP reporter timeout must be number"}}
+		httpOpts = append(httpOpts, WithHTTPTimeout(time.Second * time.Duration(httpTimeoutVal)))
+		if {{.Config.Reporter.GRPC.TLS.Enable.ToGoBoolValue}} {
+			tlsConfig, err := NewTLSConfig({{.Config.Reporter.GRPC.TLS.CAPath.ToGoStringValue}},
+				{{.Config.Reporter.GRPC.TLS.ClientKeyPath.ToGoStringValue}},
+				{{.Config.Reporter.GRPC.TLS.ClientCertChainPath.ToGoStringValue}},
+				{{.Config.Reporter.GRPC.TLS.InsecureSkipVerify.ToGoBoolValue}})
+			if err != nil {
+				panic(fmt.Sprintf("generate go agent tls config error: %v", err))
+			}
+			httpOpts = append(httpOpts, WithHTTPTLSConfig(tlsConfig))
+		}
+		return NewHTTPReporter(logger, {{.Config.Reporter.HTTP.BackendService.ToGoStringValue}}, httpOpts...)
+	}
+	if {{.Config.Reporter.OTLP.Enable.ToGoBoolValue}} {
+		var otlpOpts []OTLPReporterOption
+		otlpOpts = append(otlpOpts, WithOTLPProtocol({{.Config.Reporter.OTLP.Protocol.ToGoStringValue}}))
+		otlpCheckIntervalVal := {{.Config.Reporter.GRPC.CheckInterval.ToGoIntValue "the GRPC reporter check interval must be number"}}
+		otlpOpts = append(otlpOpts, WithOTLPCheckInterval(time.Second * time.Duration(otlpCheckIntervalVal)))
+		otlpOpts = append(otlpOpts, WithOTLPMaxSendQueueSize({{.Config.Reporter.GRPC.MaxSendQueue.ToGoIntValue "the GRPC reporter max queue size must be number"}}))
+		otlpOpts = append(otlpOpts, WithOTLPHeaders(ParseOTLPHeaders({{.Config.Reporter.OTLP.Headers.ToGoStringValue}})))
+		otlpTimeoutVal := {{.Config.Reporter.OTLP.Timeout.ToGoIntValue "the OTLP reporter timeout must be number"}}
+		otlpOpts = append(otlpOpts, WithOTLPTimeout(time.Second * time.Duration(otlpTimeoutVal)))
+		if {{.Config.Reporter.OTLP.TLS.Enable.ToGoBoolValue}} {
+			tlsConfig, err := NewTLSConfig({{.Config.Reporter.OTLP.TLS.CAPath.ToGoStringValue}},
+				{{.Config.Reporter.OTLP.TLS.ClientKeyPath.ToGoStringValue}},
+				{{.Config.Reporter.OTLP.TLS.ClientCertChainPath.ToGoStringValue}},
+				{{.Config.Reporter.OTLP.TLS.InsecureSkipVerify.ToGoBoolValue}})
+			if err != nil {
+				panic(fmt.Sprintf("generate go agent tls config error: %v", err))
+			}
+			otlpOpts = append(otlpOpts, WithOTLPTLSConfig(tlsConfig))
+		}
+		return NewOTLPReporter(logger, {{.Config.Reporter.OTLP.Endpoint.ToGoStringValue}}, otlpOpts...)
+	}
 	var opts []ReporterOption
 	checkIntervalVal := {{.Config.Reporter.GRPC.CheckInterval.ToGoIntValue "the GRPC reporter check interval must be number"}}
 	opts = append(opts, WithCheckInterval(time.Second * time.Duration(checkIntervalVal)))
-	opts = append(opts, WithMaxSendQueueSize({{.Config.Reporter.GRPC.MaxSendQueue.ToGoIntValue "the GRPC reporter max queue size must be number"}}))
+	maxSendQueueVal := {{.Config.Reporter.GRPC.MaxSendQueue.ToGoIntValue "the GRPC reporter max queue size must be number"}}
+	opts = append(opts, WithMaxSendQueueSize(maxSendQueueVal))
+	sendQueueSize := func(size int) int {
+		if size > 0 {
+			return size
+		}
+		return maxSendQueueVal
+	}
+	segmentQueueSizeVal := {{.Config.Reporter.GRPC.Queue.Segment.Size.ToGoIntValue "the GRPC reporter segment queue size must be number"}}
+	segmentQueueTimeoutVal := {{.Config.Reporter.GRPC.Queue.Segment.BlockTimeout.ToGoIntValue "the GRPC reporter segment queue block timeout must be number"}}
+	opts = append(opts, WithTracingSendQueue(sendQueueSize(segmentQueueSizeVal), DropPolicy({{.Config.Reporter.GRPC.Queue.Segment.Policy.ToGoStringValue}}),
+		time.Millisecond * time.Duration(segmentQueueTimeoutVal)))
+	meterQueueSizeVal := {{.Config.Reporter.GRPC.Queue.Meter.Size.ToGoIntValue "the GRPC reporter meter queue size must be number"}}
+	meterQueueTimeoutVal := {{.Config.Reporter.GRPC.Queue.Meter.BlockTimeout.ToGoIntValue "the GRPC reporter meter queue block timeout must be number"}}
+	opts = append(opts, WithMetricsSendQueue(sendQueueSize(meterQueueSizeVal), DropPolicy({{.Config.Reporter.GRPC.Queue.Meter.Policy.ToGoStringValue}}),
+		time.Millisecond * time.Duration(meterQueueTimeoutVal)))
+	logQueueSizeVal := {{.Config.Reporter.GRPC.Queue.Log.Size.ToGoIntValue "the GRPC reporter log queue size must be number"}}
+	logQueueTimeoutVal := {{.Config.Reporter.GRPC.Queue.Log.BlockTimeout.ToGoIntValue "the GRPC reporter log queue block timeout must be number"}}
+	opts = append(opts, WithLogSendQueue(sendQueueSize(logQueueSizeVal), DropPolicy({{.Config.Reporter.GRPC.Queue.Log.Policy.ToGoStringValue}}),
+		time.Millisecond * time.Duration(logQueueTimeoutVal)))
 	opts = append(opts, WithAuthentication({{.Config.Reporter.GRPC.Authentication.ToGoStringValue}}))
 	cdsFetchIntervalVal := {{.Config.Reporter.GRPC.CDSFetchInterval.ToGoIntValue "the GRPC reporter max queue size must be number"}}
 	opts = append(opts, WithCDS(time.Second * time.Duration(cdsFetchIntervalVal)))
@@ -145,6 +222,21 @@ func {{.InitFuncName}}(logger operator.LogOperator) (Reporter, error) {
 		}
 		opts = append(opts, WithTransportCredentials(tc))
 	}
+	if {{.Config.Reporter.GRPC.Spool.Enable.ToGoBoolValue}} {
+		spoolMaxSizeVal := {{.Config.Reporter.GRPC.Spool.MaxSize.ToGoIntValue "the GRPC reporter spool max size must be number"}}
+		spoolMaxAgeVal := {{.Config.Reporter.GRPC.Spool.MaxAge.ToGoIntValue "the GRPC reporter spool max age must be number"}}
+		opts = append(opts, WithSpool({{.Config.Reporter.GRPC.Spool.Path.ToGoStringValue}},
+			int64(spoolMaxSizeVal) * 1024 * 1024, time.Second * time.Duration(spoolMaxAgeVal)))
+	}
+	if compressor := {{.Config.Reporter.GRPC.Compressor.ToGoStringValue}}; compressor != "" {
+		opts = append(opts, WithCompressor(compressor))
+	}
+	segmentBatchSizeVal := {{.Config.Reporter.GRPC.SegmentBatch.Size.ToGoIntValue "the GRPC reporter segment batch size must be number"}}
+	if segmentBatchSizeVal > 1 {
+		segmentBatchIntervalVal := {{.Config.Reporter.GRPC.SegmentBatch.Interval.ToGoIntValue "the GRPC reporter segment batch interval must be number"}}
+		opts = append(opts, WithSegmentBatch(segmentBatchSizeVal, time.Millisecond * time.Duration(segmentBatchIntervalVal)))
+	}
+	opts = append(opts, WithStreamRecycle({{.Config.Reporter.GRPC.StreamRecycle.ToGoIntValue "the GRPC reporter stream recycle must be number"}}))
 
 	return NewGRPCReporter(logger, {{.Config.Reporter.GRPC.BackendService.ToGoStringValue}}, opts...)
 }
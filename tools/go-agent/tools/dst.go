@@ -78,7 +78,8 @@ func DeletePackageImports(file dst.Node, imports ...string) {
 				return true
 			}
 			if _, ok := deletedPackages[pkgRefName.Name]; ok {
-				RemovePackageRef(cursor.Parent(), n, -1)
+				// replace the selector itself, so the reference could be in any place, such as the arguments of calling
+				cursor.Replace(dst.NewIdent(n.Sel.Name))
 			}
 		case *dst.CaseClause:
 			for i, d := range n.List {
@@ -92,6 +92,14 @@ func TestDeletePackageImports(t *testing.T) {
 				return reflect.DeepEqual(call.Fun, dst.NewIdent("Count"))
 			},
 		},
+		{
+			goCode:  "test.Count(test.Value, 1)",
+			isValue: true,
+			validate: func(result dst.Node) bool {
+				call := result.(*dst.CallExpr)
+				return reflect.DeepEqual(call.Fun, dst.NewIdent("Count")) && reflect.DeepEqual(call.Args[0], dst.NewIdent("Value"))
+			},
+		},
 		{
 			goCode:  "[]test.Int{}",
 			isValue: true,
@@ -223,6 +223,10 @@ func addPackagePrefixForArgsAndClone(pkg string, tp dst.Expr) dst.Expr {
 		expr := dst.Clone(tp).(*dst.Ellipsis)
 		expr.Elt = addPackagePrefixForArgsAndClone(pkg, t.Elt)
 		return expr
+	case *dst.ArrayType:
+		expr := dst.Clone(tp).(*dst.ArrayType)
+		expr.Elt = addPackagePrefixForArgsAndClone(pkg, t.Elt)
+		return expr
 	case *dst.SelectorExpr:
 		exp := dst.Clone(tp).(*dst.SelectorExpr)
 		// if also contains a package prefix, then it could be reffed a package with same name
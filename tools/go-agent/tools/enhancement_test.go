@@ -139,3 +139,25 @@ func validateParameterInfo(t *testing.T, inx int, flistType FieldListType, actua
 		}
 	}
 }
+
+func TestPackagedParameterType(t *testing.T) {
+	tests := []struct {
+		funcCode string
+		expected []string
+	}{
+		{
+			funcCode: `func Test(a int, b *Field, c []Field, d ...Option) {}`,
+			expected: []string{"int", "*trace.Field", "[]trace.Field", "[]trace.Option"},
+		},
+	}
+	for i, test := range tests {
+		fun := GoStringToDecls(test.funcCode)[0].(*dst.FuncDecl)
+		params := EnhanceParameterNames(fun.Type.Params, FieldListTypeParam)
+		for j, p := range params {
+			actual := (&PackagedParameterInfo{ParameterInfo: *p, PackageName: "trace"}).PackagedTypeName()
+			if actual != test.expected[j] {
+				t.Errorf("case %d: expected type %s, actual %s", i, test.expected[j], actual)
+			}
+		}
+	}
+}